@@ -113,7 +113,6 @@ func NewAPIRequest() *APIRequest {
 	}
 
 	err = json.Unmarshal(file, &request)
-	fmt.Println(request)
 	if err != nil {
 		log.Fatalln("Error file Unmarshal")
 		return &request
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/solapi/solapi-go/v2/client"
+	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	apiSecret := os.Getenv("API_SECRET")
+	if apiKey == "" || apiSecret == "" {
+		fmt.Println("환경변수(API_KEY, API_SECRET)가 필요합니다.")
+		os.Exit(1)
+	}
+
+	c := client.NewClient(apiKey, apiSecret)
+	ctx := context.Background()
+
+	// 그룹 생성, 메시지 분할 추가, 발송을 Campaign 이 대신 처리합니다.
+	campaign := c.Groups.NewCampaign(groups.CampaignOptions{
+		Group:       groups.CreateGroupOptions{AllowDuplicates: true},
+		ChunkSize:   1000,
+		Concurrency: 4,
+	})
+
+	// 반드시 발신번호/수신번호는 01000000000 형식으로 입력해야 합니다.
+	for i := 0; i < 3; i++ {
+		msg := messages.Message{To: "수신번호", From: "계정에 등록한 발신번호", Text: fmt.Sprintf("캠페인 메시지 %d", i+1)}
+		if err := campaign.Add(ctx, msg); err != nil {
+			fmt.Println("add error:", err)
+			_ = campaign.Abort(ctx)
+			os.Exit(1)
+		}
+	}
+
+	// 오류가 발생하면 그룹은 자동으로 삭제됩니다.
+	res, err := campaign.Send(ctx)
+	if err != nil {
+		fmt.Println("campaign error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("groupId=%s added=%d failed=%d status=%s\n", res.GroupID, res.Added, len(res.FailedMessageList), res.Response.Status)
+}
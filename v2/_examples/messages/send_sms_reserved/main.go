@@ -15,8 +15,8 @@ import (
 func sendReservedSMS(ctx context.Context, apiKey, apiSecret, to, from string, delay time.Duration) (messages.DetailGroupMessageResponse, error) {
 	c := client.NewClient(apiKey, apiSecret)
 
-	// delay 뒤 예약발송 시간 (RFC3339, UTC)
-	scheduledAt := time.Now().UTC().Add(delay).Format(time.RFC3339)
+	// delay 뒤 예약발송 시간 (SDK 가 UTC ISO-8601 로 변환하고 유효 범위를 검사합니다)
+	scheduledAt := time.Now().Add(delay)
 
 	msg := messages.Message{
 		To:   to,
@@ -26,7 +26,7 @@ func sendReservedSMS(ctx context.Context, apiKey, apiSecret, to, from string, de
 
 	showMessageList := true
 
-	return c.Messages.Send(ctx, msg, messages.SendOptions{ScheduledDate: scheduledAt, ShowMessageList: &showMessageList})
+	return c.Messages.Send(ctx, msg, messages.SendOptions{ScheduleAt: scheduledAt, ShowMessageList: &showMessageList})
 }
 
 func main() {
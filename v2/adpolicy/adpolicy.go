@@ -0,0 +1,207 @@
+// Package adpolicy enforces Korean rules for advertising messages: the
+// "(광고)" prefix, a free opt-out number, and no sends between 21:00 and
+// 08:00 KST without the recipient's consent.
+//
+// A message is an ad when Message.Ad is set or, for Kakao messages,
+// KakaoOptions.AdFlag is true.
+//
+//	p := adpolicy.Policy{OptOutNumber: "080-123-4567", Inject: true, Reschedule: true}
+//	if err := p.Apply(&req); err != nil { ... }
+//	res, err := c.Messages.SendManyDetail(ctx, req)
+package adpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// Prefix must open the text of an ad.
+const Prefix = "(광고)"
+
+// Quiet hours in KST during which ads may not be sent without consent.
+const (
+	QuietStartHour = 21
+	QuietEndHour   = 8
+)
+
+var (
+	ErrMissingPrefix  = errors.New("adpolicy: ad text must start with " + Prefix)
+	ErrMissingOptOut  = errors.New("adpolicy: ad text must include the opt-out number")
+	ErrNoOptOutNumber = errors.New("adpolicy: OptOutNumber is required")
+	ErrQuietHours     = errors.New("adpolicy: ads cannot be sent between 21:00 and 08:00 KST")
+	ErrTooLong        = fmt.Errorf("adpolicy: ad text exceeds %d bytes", messages.LMSMaxBytes)
+)
+
+// timeNow is a seam for tests.
+var timeNow = time.Now
+
+// Policy checks or fixes ad messages.
+type Policy struct {
+	// OptOutNumber is the free opt-out number, e.g. "080-123-4567".
+	OptOutNumber string
+	// FooterFormat renders the opt-out footer from OptOutNumber; the default
+	// is "무료수신거부 %s".
+	FooterFormat string
+	// Inject adds a missing prefix and footer instead of failing.
+	Inject bool
+	// Reschedule moves sends that would land in quiet hours to the next
+	// 08:00 KST instead of failing with ErrQuietHours.
+	Reschedule bool
+	// Consented skips the quiet-hours check for recipients who agreed to
+	// receive ads at night.
+	Consented bool
+}
+
+// IsAd reports whether m is flagged as an advertisement.
+func IsAd(m messages.Message) bool {
+	if m.Ad {
+		return true
+	}
+	return m.KakaoOptions != nil && m.KakaoOptions.AdFlag != nil && *m.KakaoOptions.AdFlag
+}
+
+// InQuietHours reports whether t falls between 21:00 and 08:00 KST.
+func InQuietHours(t time.Time) bool {
+	h := t.In(messages.KST).Hour()
+	return h >= QuietStartHour || h < QuietEndHour
+}
+
+// NextAllowed returns t if it is outside quiet hours, or the following
+// 08:00 KST otherwise.
+func NextAllowed(t time.Time) time.Time {
+	if !InQuietHours(t) {
+		return t
+	}
+	k := t.In(messages.KST)
+	next := time.Date(k.Year(), k.Month(), k.Day(), QuietEndHour, 0, 0, 0, messages.KST)
+	if k.Hour() >= QuietStartHour {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func (p *Policy) footer() string {
+	format := p.FooterFormat
+	if format == "" {
+		format = "무료수신거부 %s"
+	}
+	return fmt.Sprintf(format, p.OptOutNumber)
+}
+
+// ApplyMessage validates an ad text message, or fixes it when Inject is set,
+// and recomputes its type from the resulting length. Messages that are not
+// ads are returned unchanged.
+//
+// Kakao ads, such as BMS and friend-talk messages with AdFlag, are labeled
+// by the channel, so their text is never rewritten and needs no prefix; it
+// must still carry the opt-out number. AlimTalk templates are left alone.
+func (p *Policy) ApplyMessage(m messages.Message) (messages.Message, error) {
+	if !IsAd(m) {
+		return m, nil
+	}
+	if m.KakaoOptions != nil {
+		return m, p.checkKakao(m)
+	}
+	if !messages.IsTextType(m.Type) {
+		return m, nil
+	}
+	if p.OptOutNumber == "" {
+		return m, ErrNoOptOutNumber
+	}
+	text := strings.TrimSpace(m.Text)
+	if !strings.HasPrefix(text, Prefix) {
+		if !p.Inject {
+			return m, ErrMissingPrefix
+		}
+		text = Prefix + " " + text
+	}
+	if !containsDigits(text, p.OptOutNumber) {
+		if !p.Inject {
+			return m, ErrMissingOptOut
+		}
+		text += "\n" + p.footer()
+	}
+	if messages.TextBytes(text) > messages.LMSMaxBytes {
+		return m, ErrTooLong
+	}
+	m.Text = text
+	if m.Type != "" {
+		m.Type = messages.DetectType(m)
+	}
+	return m, nil
+}
+
+// Apply runs ApplyMessage on every message of req and, if req contains ads,
+// checks when it will be delivered: at its scheduled date or now. A send
+// landing in quiet hours fails with ErrQuietHours, or is scheduled for the
+// next 08:00 KST when Reschedule is set. req is only modified on success.
+func (p *Policy) Apply(req *messages.SendRequest) error {
+	msgs := make([]messages.Message, len(req.Messages))
+	hasAd := false
+	for i, m := range req.Messages {
+		out, err := p.ApplyMessage(m)
+		if err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+		msgs[i] = out
+		hasAd = hasAd || IsAd(m)
+	}
+	if !hasAd || p.Consented {
+		req.Messages = msgs
+		return nil
+	}
+
+	at := req.ScheduleAt
+	if at.IsZero() && req.ScheduledDate != "" {
+		t, err := messages.ParseDate(req.ScheduledDate)
+		if err != nil {
+			return err
+		}
+		at = t
+	}
+	scheduled := !at.IsZero()
+	if !scheduled {
+		at = timeNow()
+	}
+	if InQuietHours(at) {
+		if !p.Reschedule {
+			return ErrQuietHours
+		}
+		req.ScheduleAt, req.ScheduledDate = NextAllowed(at), ""
+	}
+	req.Messages = msgs
+	return nil
+}
+
+// checkKakao validates a Kakao ad without changing it.
+func (p *Policy) checkKakao(m messages.Message) error {
+	if m.Type == "ATA" || m.Text == "" {
+		return nil
+	}
+	if p.OptOutNumber == "" {
+		return ErrNoOptOutNumber
+	}
+	if !containsDigits(m.Text, p.OptOutNumber) {
+		return ErrMissingOptOut
+	}
+	return nil
+}
+
+// containsDigits reports whether the digits of number appear in s, ignoring
+// separators such as dashes.
+func containsDigits(s, number string) bool {
+	return strings.Contains(digits(s), digits(number))
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,119 @@
+package adpolicy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestApplyMessage_Inject(t *testing.T) {
+	p := Policy{OptOutNumber: "080-123-4567", Inject: true}
+	m, err := p.ApplyMessage(messages.Message{Ad: true, Type: "SMS", Text: strings.Repeat("가", 40)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(m.Text, Prefix) || !strings.HasSuffix(m.Text, "무료수신거부 080-123-4567") {
+		t.Fatalf("unexpected text: %q", m.Text)
+	}
+	if m.Type != messages.TypeLMS {
+		t.Fatalf("expected type to grow to LMS, got %s", m.Type)
+	}
+
+	plain := messages.Message{Type: "SMS", Text: "hello"}
+	if out, err := p.ApplyMessage(plain); err != nil || out.Text != "hello" {
+		t.Fatalf("non-ads must be untouched: %q %v", out.Text, err)
+	}
+}
+
+func TestApplyMessage_Validate(t *testing.T) {
+	p := Policy{OptOutNumber: "0801234567"}
+	if _, err := p.ApplyMessage(messages.Message{Ad: true, Text: "sale"}); !errors.Is(err, ErrMissingPrefix) {
+		t.Fatalf("expected ErrMissingPrefix, got %v", err)
+	}
+	if _, err := p.ApplyMessage(messages.Message{Ad: true, Text: "(광고) sale"}); !errors.Is(err, ErrMissingOptOut) {
+		t.Fatalf("expected ErrMissingOptOut, got %v", err)
+	}
+	if _, err := p.ApplyMessage(messages.Message{Ad: true, Text: "(광고) sale\n무료거부 080-123-4567"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApply_QuietHours(t *testing.T) {
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return time.Date(2026, 3, 2, 22, 30, 0, 0, messages.KST) }
+
+	ad := messages.Message{Ad: true, Text: "(광고) sale 080-123-4567"}
+	block := Policy{OptOutNumber: "080-123-4567"}
+	req := messages.SendRequest{Messages: []messages.Message{ad}}
+	if err := block.Apply(&req); !errors.Is(err, ErrQuietHours) {
+		t.Fatalf("expected ErrQuietHours, got %v", err)
+	}
+
+	resched := Policy{OptOutNumber: "080-123-4567", Reschedule: true}
+	if err := resched.Apply(&req); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2026, 3, 3, 8, 0, 0, 0, messages.KST)
+	if !req.ScheduleAt.Equal(want) {
+		t.Fatalf("rescheduled to %v, want %v", req.ScheduleAt, want)
+	}
+
+	// a daytime schedule is kept; non-ad sends are never blocked
+	day := messages.SendRequest{Messages: []messages.Message{ad}, ScheduledDate: "2026-03-03 10:00"}
+	if err := block.Apply(&day); err != nil || day.ScheduledDate == "" {
+		t.Fatalf("daytime schedule must pass: %v", err)
+	}
+	plain := messages.SendRequest{Messages: []messages.Message{{Text: "hi"}}}
+	if err := block.Apply(&plain); err != nil {
+		t.Fatal(err)
+	}
+	consent := Policy{OptOutNumber: "080-123-4567", Consented: true}
+	if err := consent.Apply(&messages.SendRequest{Messages: []messages.Message{ad}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNextAllowed(t *testing.T) {
+	early := time.Date(2026, 3, 3, 3, 0, 0, 0, messages.KST)
+	if got := NextAllowed(early); !got.Equal(time.Date(2026, 3, 3, 8, 0, 0, 0, messages.KST)) {
+		t.Fatalf("got %v", got)
+	}
+	noon := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC) // 21:00 KST
+	if !InQuietHours(noon) {
+		t.Fatal("21:00 KST is quiet")
+	}
+}
+
+func TestApply_KakaoBMSAd(t *testing.T) {
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return time.Date(2026, 3, 2, 10, 0, 0, 0, messages.KST) }
+
+	adFlag := true
+	bms := messages.Message{
+		Type:         "BMS_TEXT",
+		Text:         "봄맞이 할인",
+		KakaoOptions: &messages.KakaoOptions{PfID: "PF", AdFlag: &adFlag, BMS: &messages.KakaoBMSOptions{Targeting: "I"}},
+	}
+	p := Policy{OptOutNumber: "080-123-4567", Inject: true}
+	if _, err := p.ApplyMessage(bms); !errors.Is(err, ErrMissingOptOut) {
+		t.Fatalf("expected ErrMissingOptOut, got %v", err)
+	}
+
+	bms.Text = "봄맞이 할인\n수신거부 080-123-4567"
+	out, err := p.ApplyMessage(bms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Text != bms.Text {
+		t.Fatalf("Kakao text must not be rewritten: %q", out.Text)
+	}
+
+	timeNow = func() time.Time { return time.Date(2026, 3, 2, 22, 30, 0, 0, messages.KST) }
+	req := messages.SendRequest{Messages: []messages.Message{bms}}
+	if err := p.Apply(&req); !errors.Is(err, ErrQuietHours) {
+		t.Fatalf("expected ErrQuietHours for a BMS ad, got %v", err)
+	}
+}
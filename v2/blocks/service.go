@@ -0,0 +1,85 @@
+// Package blocks manages the account's block list and 080 opt-outs, and
+// provides a Suppression filter that keeps sends away from those numbers.
+package blocks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/solapi/solapi-go/v2/instrumentation"
+	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/internal/transport"
+)
+
+// Service exposes block list endpoints.
+type Service struct {
+	baseURL    string
+	creds      auth.CredentialsProvider
+	httpClient *http.Client
+}
+
+func NewService(baseURL string, creds auth.CredentialsProvider) *Service {
+	return &Service{baseURL: baseURL, creds: creds, httpClient: http.DefaultClient}
+}
+
+// List GET /iam/v1/block/numbers
+func (s *Service) List(ctx context.Context, q ListQuery) (ListResponse, error) {
+	values := url.Values{}
+	if q.StartKey != "" {
+		values.Set("startKey", q.StartKey)
+	}
+	if q.Limit > 0 {
+		values.Set("limit", fmt.Sprintf("%d", q.Limit))
+	}
+	if q.PhoneNumber != "" {
+		values.Set("phoneNumber", q.PhoneNumber)
+	}
+	urlStr := fmt.Sprintf("%s/iam/v1/block/numbers", s.baseURL)
+	if enc := values.Encode(); enc != "" {
+		urlStr += "?" + enc
+	}
+	req := transport.DefaultRequest{URL: urlStr, Method: "GET", Operation: "blocks.list"}
+	ctx = s.withTransport(ctx)
+	return transport.FetchJSON[struct{}, ListResponse](ctx, s.creds, req, nil)
+}
+
+// Add POST /iam/v1/block/numbers
+func (s *Service) Add(ctx context.Context, body AddRequest) (AddResponse, error) {
+	urlStr := fmt.Sprintf("%s/iam/v1/block/numbers", s.baseURL)
+	req := transport.DefaultRequest{URL: urlStr, Method: "POST", Operation: "blocks.add"}
+	ctx = s.withTransport(ctx)
+	return transport.FetchJSON[AddRequest, AddResponse](ctx, s.creds, req, &body)
+}
+
+// Remove DELETE /iam/v1/block/numbers/{blockNumberId}
+func (s *Service) Remove(ctx context.Context, blockNumberID string) (BlockNumber, error) {
+	urlStr := fmt.Sprintf("%s/iam/v1/block/numbers/%s", s.baseURL, url.PathEscape(blockNumberID))
+	req := transport.DefaultRequest{
+		URL:       urlStr,
+		Method:    "DELETE",
+		Operation: "blocks.remove",
+		Attrs:     []instrumentation.Attribute{instrumentation.String("solapi.block_number_id", blockNumberID)},
+	}
+	ctx = s.withTransport(ctx)
+	return transport.FetchJSON[struct{}, BlockNumber](ctx, s.creds, req, nil)
+}
+
+// ListBlacks GET /iam/v1/black
+func (s *Service) ListBlacks(ctx context.Context, startKey string, limit int) (ListBlacksResponse, error) {
+	values := url.Values{}
+	if startKey != "" {
+		values.Set("startKey", startKey)
+	}
+	if limit > 0 {
+		values.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	urlStr := fmt.Sprintf("%s/iam/v1/black", s.baseURL)
+	if enc := values.Encode(); enc != "" {
+		urlStr += "?" + enc
+	}
+	req := transport.DefaultRequest{URL: urlStr, Method: "GET", Operation: "blocks.list_blacks"}
+	ctx = s.withTransport(ctx)
+	return transport.FetchJSON[struct{}, ListBlacksResponse](ctx, s.creds, req, nil)
+}
@@ -0,0 +1,52 @@
+package blocks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestBlocks_AddListRemove(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/iam/v1/block/numbers":
+			var body AddRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if len(body.PhoneNumbers) != 1 || body.Memo != "opt-out" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(AddResponse{BlockNumbers: []BlockNumber{{BlockNumberID: "B1", PhoneNumber: body.PhoneNumbers[0]}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/iam/v1/block/numbers":
+			if r.URL.Query().Get("limit") != "5" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(ListResponse{BlockNumbers: []BlockNumber{{BlockNumberID: "B1", PhoneNumber: "01012345678"}}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/iam/v1/block/numbers/B1":
+			_ = json.NewEncoder(w).Encode(BlockNumber{BlockNumberID: "B1"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	ctx := context.Background()
+	added, err := svc.Add(ctx, AddRequest{PhoneNumbers: []string{"01012345678"}, Memo: "opt-out"})
+	if err != nil || added.BlockNumbers[0].BlockNumberID != "B1" {
+		t.Fatalf("add: %+v %v", added, err)
+	}
+	list, err := svc.List(ctx, ListQuery{Limit: 5})
+	if err != nil || len(list.BlockNumbers) != 1 {
+		t.Fatalf("list: %+v %v", list, err)
+	}
+	if _, err := svc.Remove(ctx, "B1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package blocks
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// Reasons reported for suppressed recipients.
+const (
+	ReasonBlocked = "blocked number"
+	ReasonOptOut  = "080 opt-out"
+)
+
+// Suppression is a messages.RecipientFilter that drops recipients on a local
+// suppression list. Entries can be global or limited to one sender number,
+// as 080 opt-outs are. Numbers are compared by their digits.
+type Suppression struct {
+	mu     sync.RWMutex
+	global map[string]string
+	sender map[[2]string]string
+}
+
+// NewSuppression returns an empty list.
+func NewSuppression() *Suppression {
+	return &Suppression{global: map[string]string{}, sender: map[[2]string]string{}}
+}
+
+// Add suppresses number for every sender.
+func (s *Suppression) Add(number, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.global[digits(number)] = reason
+}
+
+// AddForSender suppresses number only for messages from sender.
+func (s *Suppression) AddForSender(number, sender, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sender[[2]string{digits(number), digits(sender)}] = reason
+}
+
+// Remove lifts every suppression of number.
+func (s *Suppression) Remove(number string) {
+	d := digits(number)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.global, d)
+	for k := range s.sender {
+		if k[0] == d {
+			delete(s.sender, k)
+		}
+	}
+}
+
+// Len returns the number of entries.
+func (s *Suppression) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.global) + len(s.sender)
+}
+
+// Check returns the reason to is suppressed for messages from sender, or
+// false.
+func (s *Suppression) Check(to, sender string) (string, bool) {
+	d := digits(to)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if reason, ok := s.global[d]; ok {
+		return reason, true
+	}
+	reason, ok := s.sender[[2]string{d, digits(sender)}]
+	return reason, ok
+}
+
+func (s *Suppression) FilterRecipient(ctx context.Context, m messages.Message, to string) (messages.RecipientDecision, error) {
+	if reason, ok := s.Check(to, m.From); ok {
+		return messages.RecipientDecision{Action: messages.RecipientDrop, Reason: reason}, nil
+	}
+	return messages.RecipientDecision{}, nil
+}
+
+// Sync replaces the list with the account's block list and 080 opt-outs,
+// reading every page from svc.
+func (s *Suppression) Sync(ctx context.Context, svc *Service) error {
+	global := map[string]string{}
+	sender := map[[2]string]string{}
+	for startKey := ""; ; {
+		res, err := svc.List(ctx, ListQuery{StartKey: startKey})
+		if err != nil {
+			return err
+		}
+		for _, b := range res.BlockNumbers {
+			global[digits(b.PhoneNumber)] = ReasonBlocked
+		}
+		if res.NextKey == "" || res.NextKey == startKey {
+			break
+		}
+		startKey = res.NextKey
+	}
+	for startKey := ""; ; {
+		res, err := svc.ListBlacks(ctx, startKey, 0)
+		if err != nil {
+			return err
+		}
+		for _, b := range res.BlackList {
+			sender[[2]string{digits(b.RecipientNumber), digits(b.SenderNumber)}] = ReasonOptOut
+		}
+		if res.NextKey == "" || res.NextKey == startKey {
+			break
+		}
+		startKey = res.NextKey
+	}
+	s.mu.Lock()
+	s.global, s.sender = global, sender
+	s.mu.Unlock()
+	return nil
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
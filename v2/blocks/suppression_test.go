@@ -0,0 +1,64 @@
+package blocks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestSuppression_SyncAndFilter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/iam/v1/block/numbers":
+			if r.URL.Query().Get("startKey") == "" {
+				_ = json.NewEncoder(w).Encode(ListResponse{BlockNumbers: []BlockNumber{{PhoneNumber: "010-1111-1111"}}, NextKey: "p2"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(ListResponse{BlockNumbers: []BlockNumber{{PhoneNumber: "01022222222"}}})
+		case "/iam/v1/black":
+			_ = json.NewEncoder(w).Encode(ListBlacksResponse{BlackList: map[string]Black{
+				"K1": {RecipientNumber: "01033333333", SenderNumber: "029302266"},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s := NewSuppression()
+	s.Add("01099999999", "stale")
+	if err := s.Sync(context.Background(), NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})); err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 3 {
+		t.Fatalf("expected 3 entries after sync, got %d", s.Len())
+	}
+
+	msgs := []messages.Message{
+		{From: "029302266", ToList: []string{"01011111111", "01044444444"}},
+		{From: "029302266", To: "01033333333"},
+		{From: "0212345678", To: "01033333333"},
+		{From: "029302266", To: "01022222222"},
+	}
+	out, report, err := messages.FilterRecipients(context.Background(), msgs, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0].ToList[0] != "01044444444" || out[1].From != "0212345678" {
+		t.Fatalf("unexpected messages: %+v", out)
+	}
+	if len(report) != 3 || report[0].Reason != ReasonBlocked || report[1].Reason != ReasonOptOut {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	s.Remove("01022222222")
+	if _, ok := s.Check("01022222222", ""); ok {
+		t.Fatal("removed number still suppressed")
+	}
+}
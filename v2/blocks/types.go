@@ -0,0 +1,53 @@
+package blocks
+
+// BlockNumber is a number the account refuses to send to.
+type BlockNumber struct {
+	BlockNumberID string   `json:"blockNumberId"`
+	AccountID     string   `json:"accountId"`
+	PhoneNumber   string   `json:"phoneNumber"`
+	Memo          string   `json:"memo"`
+	BlockGroupIDs []string `json:"blockGroupIds"`
+	DateCreated   string   `json:"dateCreated"`
+	DateUpdated   string   `json:"dateUpdated"`
+}
+
+type ListQuery struct {
+	StartKey    string
+	Limit       int
+	PhoneNumber string
+}
+
+type ListResponse struct {
+	BlockNumbers []BlockNumber `json:"blockNumbers"`
+	Limit        int           `json:"limit"`
+	StartKey     *string       `json:"startKey"`
+	NextKey      string        `json:"nextKey"`
+}
+
+type AddRequest struct {
+	PhoneNumbers  []string `json:"phoneNumbers"`
+	Memo          string   `json:"memo,omitempty"`
+	BlockGroupIDs []string `json:"blockGroupIds,omitempty"`
+}
+
+type AddResponse struct {
+	BlockNumbers []BlockNumber `json:"blockNumbers"`
+}
+
+// Black is an 080 opt-out: RecipientNumber refused messages from
+// SenderNumber.
+type Black struct {
+	BlackID         string `json:"blackId"`
+	Type            string `json:"type"`
+	SenderNumber    string `json:"senderNumber"`
+	RecipientNumber string `json:"recipientNumber"`
+	DateCreated     string `json:"dateCreated"`
+	DateUpdated     string `json:"dateUpdated"`
+}
+
+type ListBlacksResponse struct {
+	BlackList map[string]Black `json:"blackList"`
+	Limit     int              `json:"limit"`
+	StartKey  *string          `json:"startKey"`
+	NextKey   string           `json:"nextKey"`
+}
@@ -0,0 +1,390 @@
+// Package bulk sends large message streams through send-many/detail with a
+// pool of workers.
+//
+// Messages are read from a channel or an iterator and grouped into batches
+// that are flushed when they reach BatchSize or when BatchWindow passes
+// without filling them. Workers send batches concurrently, optionally paced
+// by a rate limit, and progress is reported while the stream is drained.
+//
+//	s := bulk.New(c.Messages, bulk.Options{Workers: 8, Progress: func(e bulk.Event) {
+//		log.Printf("%d/%d registered, %.0f/s", e.Registered, e.Queued, e.Throughput)
+//	}})
+//	report, err := s.Send(ctx, ch)
+package bulk
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/ratelimit"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// Defaults applied to zero Options fields.
+const (
+	DefaultWorkers          = 4
+	DefaultBatchSize        = 1000
+	DefaultBatchWindow      = time.Second
+	DefaultProgressInterval = time.Second
+	// MaxBatchSize is the most messages send-many/detail accepts at once.
+	MaxBatchSize = 10000
+)
+
+// MessageSender sends one batch; *messages.Service implements it.
+type MessageSender interface {
+	SendManyDetail(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error)
+}
+
+// Options configures a Sender.
+type Options struct {
+	// Workers is the number of batches sent concurrently.
+	Workers int
+	// BatchSize is the number of messages per request, up to MaxBatchSize.
+	BatchSize int
+	// BatchWindow is the longest a partial batch waits for more messages.
+	BatchWindow time.Duration
+
+	// RequestsPerSecond paces batch requests across all workers; zero means
+	// no limit beyond the client's own. Burst defaults to 1.
+	RequestsPerSecond float64
+	Burst             int
+
+	// AppId and AllowDuplicates are forwarded with every batch.
+	AppId           string
+	AllowDuplicates *bool
+
+	// Progress receives an Event every ProgressInterval and once more when
+	// the stream is done. It is called from one goroutine at a time.
+	Progress         func(Event)
+	ProgressInterval time.Duration
+}
+
+// Event is a progress snapshot. Counts are per recipient, so a message with
+// a ToList counts once for every entry.
+type Event struct {
+	Queued     int
+	Registered int
+	Failed     int
+	Dropped    int
+	Batches    int
+	Elapsed    time.Duration
+	// Throughput is registered recipients per second since the start.
+	Throughput float64
+	// Done is set on the last event.
+	Done bool
+}
+
+// BatchError records a batch that failed as a whole.
+type BatchError struct {
+	// Offset is the position of the batch's first message in the stream.
+	Offset   int
+	Messages []messages.Message
+	Err      error
+}
+
+func (e *BatchError) Error() string { return e.Err.Error() }
+
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// Report summarizes a finished stream.
+type Report struct {
+	Event
+	// Unsent is the number of recipients read but not sent because the
+	// context was cancelled.
+	Unsent   int
+	GroupIDs []string
+	// FailedMessageList aggregates registration failures of all batches.
+	FailedMessageList []messages.FailedMessage
+	// Filtered aggregates recipients removed or rewritten by recipient
+	// filters. Indexes are positions in the stream.
+	Filtered []messages.FilteredRecipient
+	// Errors lists the batches that failed as a whole; their recipients are
+	// counted in Failed.
+	Errors []*BatchError
+}
+
+// Sender sends message streams in batches with a pool of workers.
+type Sender struct {
+	svc     MessageSender
+	opt     Options
+	limiter *ratelimit.Limiter
+}
+
+// New returns a Sender sending through svc.
+func New(svc MessageSender, opt Options) *Sender {
+	if opt.Workers <= 0 {
+		opt.Workers = DefaultWorkers
+	}
+	if opt.BatchSize <= 0 || opt.BatchSize > MaxBatchSize {
+		opt.BatchSize = DefaultBatchSize
+	}
+	if opt.BatchWindow <= 0 {
+		opt.BatchWindow = DefaultBatchWindow
+	}
+	if opt.ProgressInterval <= 0 {
+		opt.ProgressInterval = DefaultProgressInterval
+	}
+	s := &Sender{svc: svc, opt: opt}
+	if opt.RequestsPerSecond > 0 {
+		s.limiter = ratelimit.New(opt.RequestsPerSecond, opt.Burst)
+	}
+	return s
+}
+
+// SendSeq is like Send but reads messages from seq.
+func (s *Sender) SendSeq(ctx context.Context, seq iter.Seq[messages.Message]) (Report, error) {
+	ch := make(chan messages.Message)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(ch)
+		for m := range seq {
+			select {
+			case ch <- m:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return s.Send(ctx, ch)
+}
+
+// Send reads messages from ch until it is closed and sends them. When ctx
+// is cancelled Send stops reading, lets batches already being sent finish,
+// and returns the report together with ctx.Err(); messages not yet sent
+// are counted in Report.Unsent. Batch failures do not stop the stream; they
+// are collected in the report.
+func (s *Sender) Send(ctx context.Context, ch <-chan messages.Message) (Report, error) {
+	r := &run{s: s, start: time.Now()}
+	batches := make(chan batch)
+	var wg sync.WaitGroup
+	for i := 0; i < s.opt.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				r.send(ctx, b)
+			}
+		}()
+	}
+	stopProgress := r.progress()
+
+	r.read(ctx, ch, batches)
+	close(batches)
+	wg.Wait()
+	stopProgress()
+
+	rep := r.report()
+	if s.opt.Progress != nil {
+		s.opt.Progress(rep.Event)
+	}
+	return rep, ctx.Err()
+}
+
+type batch struct {
+	offset int
+	msgs   []messages.Message
+}
+
+// run holds the state of one Send call.
+type run struct {
+	s     *Sender
+	start time.Time
+
+	mu     sync.Mutex
+	ev     Event
+	rep    Report
+	groups map[string]bool
+}
+
+// read batches messages from ch until it is closed or ctx is done.
+func (r *run) read(ctx context.Context, ch <-chan messages.Message, out chan<- batch) {
+	var buf []messages.Message
+	offset := 0
+	timer := time.NewTimer(r.s.opt.BatchWindow)
+	timer.Stop()
+	defer timer.Stop()
+
+	flush := func() bool {
+		if len(buf) == 0 {
+			return true
+		}
+		b := batch{offset: offset, msgs: buf}
+		select {
+		case out <- b:
+			offset += len(buf)
+			buf = nil
+			timer.Stop()
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	defer func() { r.unsent(buf) }()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if !flush() {
+				return
+			}
+		case m, ok := <-ch:
+			if !ok {
+				flush()
+				return
+			}
+			if len(buf) == 0 {
+				timer.Reset(r.s.opt.BatchWindow)
+			}
+			buf = append(buf, m)
+			r.add(func(ev *Event) { ev.Queued += recipients(m) })
+			if len(buf) >= r.s.opt.BatchSize && !flush() {
+				return
+			}
+		}
+	}
+}
+
+// send sends one batch. Once the rate limiter lets it through the batch is
+// sent even if ctx is cancelled, so that shutdown does not abandon requests
+// in flight.
+func (r *run) send(ctx context.Context, b batch) {
+	if err := r.s.limiter.Wait(ctx); err != nil {
+		r.unsent(b.msgs)
+		return
+	}
+	if ctx.Err() != nil {
+		r.unsent(b.msgs)
+		return
+	}
+	res, err := r.s.svc.SendManyDetail(context.WithoutCancel(ctx), messages.SendRequest{
+		Messages:        b.msgs,
+		AppId:           r.s.opt.AppId,
+		AllowDuplicates: r.s.opt.AllowDuplicates,
+	})
+	total := 0
+	for _, m := range b.msgs {
+		total += recipients(m)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ev.Batches++
+	var notReceived *messages.MessageNotReceivedError
+	switch {
+	case err == nil:
+		dropped := 0
+		for _, f := range res.Filtered {
+			if f.Action == messages.RecipientDrop {
+				dropped++
+			}
+		}
+		r.ev.Dropped += dropped
+		r.ev.Failed += len(res.FailedMessageList)
+		r.ev.Registered += total - dropped - len(res.FailedMessageList)
+		r.rep.FailedMessageList = append(r.rep.FailedMessageList, res.FailedMessageList...)
+		r.addGroup(res)
+	case errors.Is(err, messages.ErrNoRecipients):
+		r.ev.Dropped += total
+	case errors.As(err, &notReceived):
+		r.ev.Failed += total
+		r.rep.FailedMessageList = append(r.rep.FailedMessageList, notReceived.FailedMessageList...)
+	default:
+		r.ev.Failed += total
+		r.rep.Errors = append(r.rep.Errors, &BatchError{Offset: b.offset, Messages: b.msgs, Err: err})
+	}
+	for _, f := range res.Filtered {
+		f.Index += b.offset
+		r.rep.Filtered = append(r.rep.Filtered, f)
+	}
+}
+
+func (r *run) addGroup(res messages.DetailGroupMessageResponse) {
+	id := res.GroupInfo.ID
+	if id == "" {
+		id = res.GroupID
+	}
+	if id == "" || r.groups[id] {
+		return
+	}
+	if r.groups == nil {
+		r.groups = map[string]bool{}
+	}
+	r.groups[id] = true
+	r.rep.GroupIDs = append(r.rep.GroupIDs, id)
+}
+
+func (r *run) unsent(msgs []messages.Message) {
+	n := 0
+	for _, m := range msgs {
+		n += recipients(m)
+	}
+	r.mu.Lock()
+	r.rep.Unsent += n
+	r.mu.Unlock()
+}
+
+func (r *run) add(fn func(ev *Event)) {
+	r.mu.Lock()
+	fn(&r.ev)
+	r.mu.Unlock()
+}
+
+// snapshot returns the current event; r.mu must be held.
+func (r *run) snapshot() Event {
+	ev := r.ev
+	ev.Elapsed = time.Since(r.start)
+	if s := ev.Elapsed.Seconds(); s > 0 {
+		ev.Throughput = float64(ev.Registered) / s
+	}
+	return ev
+}
+
+// progress emits events until the returned function is called.
+func (r *run) progress() (stop func()) {
+	if r.s.opt.Progress == nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		t := time.NewTicker(r.s.opt.ProgressInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				r.mu.Lock()
+				ev := r.snapshot()
+				r.mu.Unlock()
+				r.s.opt.Progress(ev)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+func (r *run) report() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep := r.rep
+	rep.Event = r.snapshot()
+	rep.Done = true
+	return rep
+}
+
+func recipients(m messages.Message) int {
+	if n := len(m.ToList); n > 0 {
+		return n
+	}
+	return 1
+}
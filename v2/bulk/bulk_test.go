@@ -0,0 +1,212 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+type senderFunc func(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error)
+
+func (f senderFunc) SendManyDetail(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+	return f(ctx, req)
+}
+
+func msgs(n int) []messages.Message {
+	out := make([]messages.Message, n)
+	for i := range out {
+		out[i] = messages.Message{To: fmt.Sprintf("010%08d", i), From: "029302266", Text: "hi"}
+	}
+	return out
+}
+
+func feed(ms []messages.Message) <-chan messages.Message {
+	ch := make(chan messages.Message, len(ms))
+	for _, m := range ms {
+		ch <- m
+	}
+	close(ch)
+	return ch
+}
+
+func TestSendBatchesBySize(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	svc := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(req.Messages))
+		if req.AppId != "app" {
+			t.Errorf("AppId not forwarded: %q", req.AppId)
+		}
+		var res messages.DetailGroupMessageResponse
+		res.GroupInfo.ID = fmt.Sprintf("G%d", len(sizes))
+		return res, nil
+	})
+	rep, err := New(svc, Options{BatchSize: 10, Workers: 2, AppId: "app"}).Send(context.Background(), feed(msgs(25)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(sizes)
+	if !slices.Equal(sizes, []int{5, 10, 10}) {
+		t.Fatalf("batch sizes: %v", sizes)
+	}
+	if rep.Queued != 25 || rep.Registered != 25 || rep.Batches != 3 || len(rep.GroupIDs) != 3 || !rep.Done {
+		t.Fatalf("report: %+v", rep)
+	}
+}
+
+func TestSendFlushesAfterWindow(t *testing.T) {
+	sent := make(chan int, 1)
+	svc := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		sent <- len(req.Messages)
+		return messages.DetailGroupMessageResponse{}, nil
+	})
+	ch := make(chan messages.Message)
+	done := make(chan Report)
+	go func() {
+		rep, _ := New(svc, Options{BatchSize: 100, BatchWindow: 10 * time.Millisecond}).Send(context.Background(), ch)
+		done <- rep
+	}()
+	for _, m := range msgs(3) {
+		ch <- m
+	}
+	select {
+	case n := <-sent:
+		if n != 3 {
+			t.Fatalf("partial batch size %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("partial batch not flushed")
+	}
+	close(ch)
+	if rep := <-done; rep.Registered != 3 {
+		t.Fatalf("report: %+v", rep)
+	}
+}
+
+func TestSendCollectsFailures(t *testing.T) {
+	boom := errors.New("boom")
+	var calls atomic.Int32
+	svc := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		switch calls.Add(1) {
+		case 1:
+			return messages.DetailGroupMessageResponse{
+				FailedMessageList: []messages.FailedMessage{{To: req.Messages[0].To, StatusCode: "1062"}},
+				Filtered:          []messages.FilteredRecipient{{Index: 1, Action: messages.RecipientDrop}},
+			}, nil
+		case 2:
+			return messages.DetailGroupMessageResponse{}, boom
+		default:
+			return messages.DetailGroupMessageResponse{}, messages.ErrNoRecipients
+		}
+	})
+	ms := msgs(4)
+	ms[3].ToList = []string{"01011112222", "01033334444"}
+	rep, err := New(svc, Options{BatchSize: 2, Workers: 1}).Send(context.Background(), feed(ms))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 5 recipients: batch 1 has one failure and one drop, batch 2 fails
+	if rep.Queued != 5 || rep.Registered != 0 || rep.Failed != 4 || rep.Dropped != 1 {
+		t.Fatalf("counts: %+v", rep.Event)
+	}
+	if len(rep.Errors) != 1 || !errors.Is(rep.Errors[0], boom) || rep.Errors[0].Offset != 2 || len(rep.Errors[0].Messages) != 2 {
+		t.Fatalf("errors: %+v", rep.Errors)
+	}
+	if len(rep.FailedMessageList) != 1 || len(rep.Filtered) != 1 {
+		t.Fatalf("report: %+v", rep)
+	}
+}
+
+func TestSendDrainsOnCancel(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var inflightCancelled atomic.Bool
+	svc := senderFunc(func(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		started <- struct{}{}
+		<-release
+		inflightCancelled.Store(ctx.Err() != nil)
+		return messages.DetailGroupMessageResponse{}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan messages.Message, 10)
+	for _, m := range msgs(5) {
+		ch <- m
+	}
+	done := make(chan struct{})
+	var rep Report
+	var err error
+	go func() {
+		defer close(done)
+		rep, err = New(svc, Options{BatchSize: 2, Workers: 1}).Send(ctx, ch)
+	}()
+	<-started
+	cancel()
+	close(release)
+	<-done
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v", err)
+	}
+	if inflightCancelled.Load() {
+		t.Fatal("in-flight batch saw a cancelled context")
+	}
+	if rep.Registered != 2 || rep.Registered+rep.Unsent != rep.Queued {
+		t.Fatalf("report: %+v", rep)
+	}
+}
+
+func TestSendSeqReportsProgress(t *testing.T) {
+	var inflight, peak atomic.Int32
+	svc := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		n := inflight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inflight.Add(-1)
+		return messages.DetailGroupMessageResponse{}, nil
+	})
+	var events []Event
+	s := New(svc, Options{BatchSize: 1, Workers: 3, ProgressInterval: time.Millisecond, Progress: func(e Event) {
+		events = append(events, e)
+	}})
+	rep, err := s.SendSeq(context.Background(), slices.Values(msgs(12)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Registered != 12 || rep.Throughput <= 0 {
+		t.Fatalf("report: %+v", rep)
+	}
+	if peak.Load() > 3 {
+		t.Fatalf("peak concurrency %d exceeds workers", peak.Load())
+	}
+	last := events[len(events)-1]
+	if !last.Done || last.Registered != 12 {
+		t.Fatalf("last event: %+v", last)
+	}
+}
+
+func TestSendRateLimit(t *testing.T) {
+	svc := senderFunc(func(context.Context, messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		return messages.DetailGroupMessageResponse{}, nil
+	})
+	start := time.Now()
+	rep, err := New(svc, Options{BatchSize: 1, Workers: 4, RequestsPerSecond: 100}).Send(context.Background(), feed(msgs(4)))
+	if err != nil || rep.Registered != 4 {
+		t.Fatalf("rep=%+v err=%v", rep, err)
+	}
+	if d := time.Since(start); d < 25*time.Millisecond {
+		t.Fatalf("4 requests at 100/s took %v", d)
+	}
+}
@@ -0,0 +1,30 @@
+// Package cash reads the account's prepaid balance.
+package cash
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/internal/transport"
+)
+
+// Service exposes cash endpoints.
+type Service struct {
+	baseURL    string
+	creds      auth.CredentialsProvider
+	httpClient *http.Client
+}
+
+func NewService(baseURL string, creds auth.CredentialsProvider) *Service {
+	return &Service{baseURL: baseURL, creds: creds, httpClient: http.DefaultClient}
+}
+
+// Balance GET /cash/v1/balance
+func (s *Service) Balance(ctx context.Context) (BalanceResponse, error) {
+	urlStr := fmt.Sprintf("%s/cash/v1/balance", s.baseURL)
+	req := transport.DefaultRequest{URL: urlStr, Method: "GET", Operation: "cash.balance"}
+	ctx = s.withTransport(ctx)
+	return transport.FetchJSON[struct{}, BalanceResponse](ctx, s.creds, req, nil)
+}
@@ -0,0 +1,31 @@
+package cash
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestCash_Balance(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/cash/v1/balance" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"balance": 12345.5, "point": 100}`))
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	res, err := svc.Balance(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Balance != 12345.5 || res.Point != 100 {
+		t.Fatalf("unexpected balance: %+v", res)
+	}
+}
@@ -0,0 +1,7 @@
+package cash
+
+// BalanceResponse is the prepaid balance and points of the account, in won.
+type BalanceResponse struct {
+	Balance float64 `json:"balance"`
+	Point   float64 `json:"point"`
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/transport"
+)
+
+// CircuitState is the state of the client's circuit breaker.
+type CircuitState = transport.CircuitState
+
+const (
+	CircuitClosed   = transport.CircuitClosed
+	CircuitOpen     = transport.CircuitOpen
+	CircuitHalfOpen = transport.CircuitHalfOpen
+)
+
+// Circuit breaker defaults applied to zero CircuitBreaker fields.
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+	DefaultHalfOpenProbes   = 1
+)
+
+// CircuitBreaker configures fail-fast behavior during API incidents. While
+// the circuit is open, calls return solapierr.ErrCircuitOpen without sending
+// a request or retrying.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive 5xx responses, timeouts
+	// or other transport errors that opens the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before probing.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of requests let through after OpenTimeout;
+	// the circuit closes once all of them succeed.
+	HalfOpenProbes int
+	// OnStateChange, if set, is called synchronously on every transition,
+	// e.g. to raise an alert. It must not block.
+	OnStateChange func(from, to CircuitState)
+}
+
+// WithCircuitBreaker returns a shallow copy of Client whose services share a
+// new circuit breaker configured by cb.
+func (c *Client) WithCircuitBreaker(cb CircuitBreaker) *Client {
+	if cb.FailureThreshold <= 0 {
+		cb.FailureThreshold = DefaultFailureThreshold
+	}
+	if cb.OpenTimeout <= 0 {
+		cb.OpenTimeout = DefaultOpenTimeout
+	}
+	if cb.HalfOpenProbes <= 0 {
+		cb.HalfOpenProbes = DefaultHalfOpenProbes
+	}
+	b := transport.NewBreaker(cb.FailureThreshold, cb.OpenTimeout, cb.HalfOpenProbes, cb.OnStateChange)
+	return c.withOptions(func(o *transport.Options) {
+		o.Breaker = b
+	})
+}
+
+// CircuitState returns the state of the circuit breaker, or CircuitClosed
+// when none is configured.
+func (c *Client) CircuitState() CircuitState {
+	return c.opts.Breaker.State()
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/messages"
+	"github.com/solapi/solapi-go/v2/solapierr"
+)
+
+func TestClient_WithCircuitBreaker(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	var opened bool
+	c := newClientWithBaseURL(ts.URL, "k", "s").WithCircuitBreaker(CircuitBreaker{
+		FailureThreshold: 1,
+		OnStateChange: func(from, to CircuitState) {
+			opened = from == CircuitClosed && to == CircuitOpen
+		},
+	})
+	if _, err := c.Groups.Create(context.Background(), groups.CreateGroupOptions{}); err == nil {
+		t.Fatal("expected server error")
+	}
+	if !opened || c.CircuitState() != CircuitOpen {
+		t.Fatalf("expected open circuit, got %v", c.CircuitState())
+	}
+	if _, err := c.Messages.List(context.Background(), messages.ListQuery{}); !errors.Is(err, solapierr.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
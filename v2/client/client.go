@@ -2,9 +2,15 @@ package client
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/solapi/solapi-go/v2/blocks"
+	"github.com/solapi/solapi-go/v2/cash"
+	"github.com/solapi/solapi-go/v2/credentials"
 	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/idempotency"
 	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/internal/transport"
 	"github.com/solapi/solapi-go/v2/messages"
 	"github.com/solapi/solapi-go/v2/storages"
 )
@@ -14,11 +20,19 @@ const defaultBaseURL = "https://api.solapi.com"
 // Client aggregates all solapi services under one entrypoint.
 type Client struct {
 	baseURL    string
-	creds      auth.AuthenticationParameter
+	creds      auth.CredentialsProvider
 	httpClient *http.Client
+	opts       *transport.Options
+	dryRun     *DryRun
+	filters    []messages.RecipientFilter
+	idem       idempotency.Store
+	lookback   idempotency.Lookback
+	defaults   messages.Defaults
 	Messages   *messages.Service
 	Storages   *storages.Service
 	Groups     *groups.Service
+	Blocks     *blocks.Service
+	Cash       *cash.Service
 }
 
 // NewClient initializes with default base URL.
@@ -26,16 +40,43 @@ func NewClient(apiKey, apiSecret string) *Client {
 	return newClientWithBaseURL(defaultBaseURL, apiKey, apiSecret)
 }
 
+// NewClientWithProvider initializes with default base URL and resolves
+// credentials from p on every request, so secrets can be rotated without
+// rebuilding the client.
+func NewClientWithProvider(p credentials.Provider) *Client {
+	return newClientWithProvider(defaultBaseURL, p)
+}
+
 // newClientWithBaseURL is a test-only helper to override baseURL.
 func newClientWithBaseURL(baseURL, apiKey, apiSecret string) *Client {
-	creds := auth.AuthenticationParameter{ApiKey: apiKey, ApiSecret: apiSecret}
-	c := &Client{baseURL: baseURL, creds: creds, httpClient: http.DefaultClient}
-	c.Messages = messages.NewService(baseURL, creds)
-	c.Storages = storages.NewService(baseURL, creds)
-	c.Groups = groups.NewService(baseURL, creds)
+	return newClientWithProvider(baseURL, auth.AuthenticationParameter{ApiKey: apiKey, ApiSecret: apiSecret})
+}
+
+func newClientWithProvider(baseURL string, creds auth.CredentialsProvider) *Client {
+	opts := &transport.Options{Clock: &auth.Clock{}}
+	c := &Client{baseURL: baseURL, creds: creds, httpClient: http.DefaultClient, opts: opts}
+	c.initServices()
 	return c
 }
 
+// initServices (re)creates the services from the client's settings.
+func (c *Client) initServices() {
+	hc := transport.AttachOptions(c.serviceHTTPClient(), c.opts)
+	c.Messages = messages.NewServiceWithHTTPClient(c.baseURL, c.creds, hc)
+	c.Storages = storages.NewServiceWithHTTPClient(c.baseURL, c.creds, hc)
+	c.Groups = groups.NewServiceWithHTTPClient(c.baseURL, c.creds, hc)
+	c.Blocks = blocks.NewServiceWithHTTPClient(c.baseURL, c.creds, hc)
+	c.Cash = cash.NewServiceWithHTTPClient(c.baseURL, c.creds, hc)
+	c.Messages.SetFilters(c.filters...)
+	c.Groups.SetFilters(c.filters...)
+	c.Messages.SetIdempotencyStore(c.idem)
+	c.Groups.SetIdempotencyStore(c.idem)
+	c.Messages.SetIdempotencyLookback(c.lookback)
+	c.Groups.SetIdempotencyLookback(c.lookback)
+	c.Messages.SetDefaults(c.defaults)
+	c.Groups.SetDefaults(c.defaults)
+}
+
 // WithHTTPClient returns a shallow copy of Client using the provided http.Client.
 // If nil is passed, the receiver's client is kept.
 func (c *Client) WithHTTPClient(hc *http.Client) *Client {
@@ -45,8 +86,29 @@ func (c *Client) WithHTTPClient(hc *http.Client) *Client {
 	nc := *c
 	nc.httpClient = hc
 	// Reinitialize services to ensure direct calls use the custom http.Client
-	nc.Messages = messages.NewServiceWithHTTPClient(nc.baseURL, nc.creds, hc)
-	nc.Storages = storages.NewServiceWithHTTPClient(nc.baseURL, nc.creds, hc)
-	nc.Groups = groups.NewServiceWithHTTPClient(nc.baseURL, nc.creds, hc)
+	nc.initServices()
 	return &nc
 }
+
+// withOptions returns a shallow copy of Client whose transport options were
+// modified by fn. The receiver's options are left untouched.
+func (c *Client) withOptions(fn func(o *transport.Options)) *Client {
+	nc := *c
+	o := *c.opts
+	o.EndpointLimits = make(map[transport.EndpointClass]*transport.Limit, len(c.opts.EndpointLimits))
+	for k, v := range c.opts.EndpointLimits {
+		o.EndpointLimits[k] = v
+	}
+	fn(&o)
+	nc.opts = &o
+	nc.initServices()
+	return &nc
+}
+
+// ClockSkew returns the offset between the API server clock and the local
+// clock, as measured when a request was rejected for a stale signature date.
+// Requests are signed with this correction applied; zero means no drift was
+// detected.
+func (c *Client) ClockSkew() time.Duration {
+	return c.opts.Clock.Offset()
+}
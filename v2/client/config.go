@@ -0,0 +1,39 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/solapi/solapi-go/v2/config"
+	"github.com/solapi/solapi-go/v2/credentials"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// NewFromConfig builds a client from a config profile; see config.Load for
+// how the profile is resolved. The profile's AppId and sender number are
+// applied as defaults.
+func NewFromConfig(profile string) (*Client, error) {
+	p, err := config.Load(profile)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromProfile(p), nil
+}
+
+// NewFromProfile builds a client from an already loaded profile.
+func NewFromProfile(p config.Profile) *Client {
+	baseURL := strings.TrimRight(p.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	c := newClientWithProvider(baseURL, credentials.Static(p.APIKey, p.APISecret))
+	return c.WithDefaults(messages.Defaults{AppId: p.AppId, From: p.From})
+}
+
+// WithDefaults returns a shallow copy of Client applying d to sends, created
+// groups and added group messages that leave the fields empty.
+func (c *Client) WithDefaults(d messages.Defaults) *Client {
+	nc := *c
+	nc.defaults = d
+	nc.initServices()
+	return &nc
+}
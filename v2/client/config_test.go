@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/config"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	var body struct {
+		Messages []messages.Message `json:"messages"`
+		Agent    struct {
+			AppID string `json:"appId"`
+		} `json:"agent"`
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"groupInfo":{"count":{"total":1}}}`))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "config")
+	content := "[staging]\napi_key = K\napi_secret = S\nbase_url = " + ts.URL + "/\napp_id = APP\nfrom = 0212345678\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.EnvConfigFile, path)
+	for _, env := range []string{config.EnvAPIKey, config.EnvAPISecret, config.EnvBaseURL, config.EnvAppID, config.EnvFrom} {
+		t.Setenv(env, "")
+	}
+
+	if _, err := NewFromConfig("prod"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+	c, err := NewFromConfig("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Messages.Send(context.Background(), messages.Message{To: "01011112222", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if body.Messages[0].From != "0212345678" || body.Agent.AppID != "APP" {
+		t.Fatalf("defaults not applied: %+v app %q", body.Messages[0], body.Agent.AppID)
+	}
+
+	if _, err := c.Messages.Send(context.Background(), messages.Message{To: "010", From: "029302266", Text: "hi"}, messages.SendOptions{AppId: "OTHER"}); err != nil {
+		t.Fatal(err)
+	}
+	if body.Messages[0].From != "029302266" || body.Agent.AppID != "OTHER" {
+		t.Fatalf("explicit values overridden: %+v app %q", body.Messages[0], body.Agent.AppID)
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/credentials"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestClient_RotatedCredentialsUsedPerRequest(t *testing.T) {
+	var seen []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authz := r.Header.Get("Authorization")
+		seen = append(seen, strings.Split(strings.TrimPrefix(authz, "HMAC-SHA256 apiKey="), ",")[0])
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{}})
+	}))
+	defer ts.Close()
+
+	rot := credentials.NewRotating("key1", "secret1")
+	c := newClientWithProvider(ts.URL, rot)
+	if _, err := c.Messages.Send(context.Background(), messages.Message{To: "010"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rot.Rotate(credentials.Value{ApiKey: "key2", ApiSecret: "secret2"})
+	if _, err := c.Messages.Send(context.Background(), messages.Message{To: "010"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != "key1" || seen[1] != "key2" {
+		t.Fatalf("unexpected api keys: %v", seen)
+	}
+}
@@ -0,0 +1,401 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/blocks"
+	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// DryRunCall is a request captured by a DryRun instead of reaching the API.
+type DryRunCall struct {
+	Method string
+	Path   string
+	// Payload is the exact JSON body the SDK built, including the agent.
+	Payload json.RawMessage
+	// Response is the synthesized response body.
+	Response json.RawMessage
+}
+
+// DryRun captures sends, group mutations and block list changes and answers
+// them locally with synthesized responses. Local validation still runs, so
+// payload errors surface as they would in production. Only read-only
+// requests and file uploads are passed through to the API; any other
+// mutation is refused locally.
+//
+// Message and group IDs in responses start with "DRYRUN-". Groups created
+// during a dry run only exist in it; lookups of DRYRUN- groups and messages
+// are answered from the dry run as well.
+type DryRun struct {
+	mu     sync.Mutex
+	seq    int
+	calls  []DryRunCall
+	groups map[string]*dryRunGroup
+}
+
+type dryRunGroup struct {
+	info     messages.GroupInfo
+	messages []messages.Message
+}
+
+// NewDryRun returns an empty DryRun.
+func NewDryRun() *DryRun {
+	return &DryRun{groups: map[string]*dryRunGroup{}}
+}
+
+// Calls returns the captured calls in order.
+func (d *DryRun) Calls() []DryRunCall {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DryRunCall(nil), d.calls...)
+}
+
+// Reset drops captured calls and groups.
+func (d *DryRun) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = nil
+	d.groups = map[string]*dryRunGroup{}
+}
+
+// WithDryRun returns a shallow copy of Client whose sends and group
+// mutations are captured by d instead of calling the API. A nil d disables
+// dry-run mode.
+func (c *Client) WithDryRun(d *DryRun) *Client {
+	nc := *c
+	nc.dryRun = d
+	nc.initServices()
+	return &nc
+}
+
+// serviceHTTPClient returns the http.Client handed to services.
+func (c *Client) serviceHTTPClient() *http.Client {
+	if c.dryRun == nil {
+		return c.httpClient
+	}
+	hc := *c.httpClient
+	hc.Transport = &dryRunTransport{d: c.dryRun, base: c.httpClient.Transport}
+	return &hc
+}
+
+type dryRunTransport struct {
+	d    *DryRun
+	base http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var status int
+	var res any
+	var payload []byte
+	switch {
+	case req.Method == http.MethodGet:
+		var ok bool
+		if status, res, ok = t.d.lookup(req.URL); !ok {
+			return t.passThrough(req)
+		}
+	case req.Method == http.MethodPost && req.URL.Path == "/storage/v1/files":
+		// uploads return file IDs that later messages need
+		return t.passThrough(req)
+	default:
+		if req.Body != nil {
+			b, err := io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			payload = b
+		}
+		status, res = t.d.handle(req.Method, req.URL.Path, payload)
+	}
+	body, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	t.d.mu.Lock()
+	t.d.calls = append(t.d.calls, DryRunCall{Method: req.Method, Path: req.URL.Path, Payload: payload, Response: body})
+	t.d.mu.Unlock()
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *dryRunTransport) passThrough(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+type dryRunError struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// handle synthesizes the response to a mutation.
+func (d *DryRun) handle(method, path string, payload []byte) (int, any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	const (
+		groupsPath = "/messages/v4/groups"
+		blocksPath = "/iam/v1/block/numbers"
+	)
+	switch {
+	case method == http.MethodPost && path == "/messages/v4/send-many/detail":
+		var body struct {
+			Messages      []messages.Message `json:"messages"`
+			ScheduledDate string             `json:"scheduledDate"`
+		}
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return http.StatusBadRequest, dryRunError{"ValidationError", err.Error()}
+		}
+		g := d.newGroup()
+		g.messages = body.Messages
+		return http.StatusOK, d.dispatch(g, body.ScheduledDate)
+	case method == http.MethodPost && path == groupsPath:
+		g := d.newGroup()
+		return http.StatusOK, groups.CreateGroupResponse{GroupID: g.info.GroupID, GroupInfo: g.info}
+	case method == http.MethodPost && path == blocksPath:
+		var body blocks.AddRequest
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return http.StatusBadRequest, dryRunError{"ValidationError", err.Error()}
+		}
+		now := messages.FormatDate(time.Now())
+		res := blocks.AddResponse{BlockNumbers: make([]blocks.BlockNumber, len(body.PhoneNumbers))}
+		for i, n := range body.PhoneNumbers {
+			res.BlockNumbers[i] = blocks.BlockNumber{
+				BlockNumberID: d.nextID("B"),
+				PhoneNumber:   n,
+				Memo:          body.Memo,
+				BlockGroupIDs: body.BlockGroupIDs,
+				DateCreated:   now,
+				DateUpdated:   now,
+			}
+		}
+		return http.StatusOK, res
+	case method == http.MethodDelete && strings.HasPrefix(path, blocksPath+"/"):
+		return http.StatusOK, blocks.BlockNumber{BlockNumberID: strings.TrimPrefix(path, blocksPath+"/")}
+	case !strings.HasPrefix(path, groupsPath+"/"):
+		return http.StatusNotFound, dryRunError{"NotFound", "dry run does not support " + method + " " + path}
+	}
+
+	id, sub, _ := strings.Cut(strings.TrimPrefix(path, groupsPath+"/"), "/")
+	g, ok := d.groups[id]
+	if !ok {
+		return http.StatusNotFound, dryRunError{"GroupNotFound", "unknown dry-run group " + id}
+	}
+	switch {
+	case method == http.MethodPut && sub == "messages":
+		var body groups.AddGroupMessagesRequest
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return http.StatusBadRequest, dryRunError{"ValidationError", err.Error()}
+		}
+		for _, m := range body.Messages {
+			m.MessageID = d.nextID("M")
+			g.messages = append(g.messages, m)
+		}
+		g.info.Count.Total = g.recipients()
+		g.info.Count.RegisteredSuccess = g.recipients()
+		return http.StatusOK, groups.GroupActionResponse{GroupInfo: g.info, FailedMessageList: []messages.FailedMessage{}}
+	case method == http.MethodDelete && sub == "messages":
+		var body groups.RemoveGroupMessagesRequest
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return http.StatusBadRequest, dryRunError{"ValidationError", err.Error()}
+		}
+		remove := make(map[string]bool, len(body.MessageIDs))
+		for _, mid := range body.MessageIDs {
+			remove[mid] = true
+		}
+		kept := g.messages[:0]
+		for _, m := range g.messages {
+			if !remove[m.MessageID] {
+				kept = append(kept, m)
+			}
+		}
+		g.messages = kept
+		g.info.Count.Total = g.recipients()
+		g.info.Count.RegisteredSuccess = g.recipients()
+		return http.StatusOK, groups.GroupActionResponse{GroupInfo: g.info, FailedMessageList: []messages.FailedMessage{}}
+	case method == http.MethodPost && sub == "send":
+		return http.StatusOK, d.dispatch(g, "")
+	case method == http.MethodPost && sub == "schedule":
+		var body groups.ScheduleRequest
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return http.StatusBadRequest, dryRunError{"ValidationError", err.Error()}
+		}
+		return http.StatusOK, d.dispatch(g, body.ScheduledDate)
+	case method == http.MethodDelete && sub == "schedule":
+		g.info.Status = "PENDING"
+		g.info.ScheduledDate = ""
+		return http.StatusOK, d.detail(g)
+	case method == http.MethodDelete && sub == "":
+		delete(d.groups, id)
+		g.info.Status = "DELETED"
+		return http.StatusOK, groups.GroupResponse{GroupID: id, GroupInfo: g.info}
+	}
+	return http.StatusNotFound, dryRunError{"NotFound", "dry run does not support " + method + " " + path}
+}
+
+// lookup answers reads of DRYRUN- groups and messages. ok is false for
+// reads that go to the API.
+func (d *DryRun) lookup(u *url.URL) (status int, res any, ok bool) {
+	const groupsPath = "/messages/v4/groups/"
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if u.Path == "/messages/v4/list" {
+		q := u.Query()
+		groupID, messageID := q.Get("groupId"), q.Get("messageId")
+		if !isDryRunID(groupID) && !isDryRunID(messageID) {
+			return 0, nil, false
+		}
+		list := map[string]map[string]any{}
+		for id, g := range d.groups {
+			if groupID != "" && id != groupID {
+				continue
+			}
+			for mid, m := range g.messageList() {
+				if messageID == "" || mid == messageID {
+					list[mid] = m
+				}
+			}
+		}
+		return http.StatusOK, map[string]any{"messageList": list}, true
+	}
+	if !strings.HasPrefix(u.Path, groupsPath) {
+		return 0, nil, false
+	}
+	id, sub, _ := strings.Cut(strings.TrimPrefix(u.Path, groupsPath), "/")
+	if !isDryRunID(id) {
+		return 0, nil, false
+	}
+	g, found := d.groups[id]
+	switch {
+	case !found:
+		return http.StatusNotFound, dryRunError{"GroupNotFound", "unknown dry-run group " + id}, true
+	case sub == "":
+		return http.StatusOK, groups.GroupResponse{GroupID: id, GroupInfo: g.info}, true
+	case sub == "messages":
+		return http.StatusOK, map[string]any{"messageList": g.messageList()}, true
+	}
+	return http.StatusNotFound, dryRunError{"NotFound", "dry run does not support GET " + u.Path}, true
+}
+
+func isDryRunID(id string) bool {
+	return strings.HasPrefix(id, "DRYRUN-")
+}
+
+// messageList returns the group's messages that have IDs, keyed by ID, in
+// the shape of a list response. Message.MarshalJSON only writes send
+// fields, so the list fields are added to its output.
+func (g *dryRunGroup) messageList() map[string]map[string]any {
+	list := make(map[string]map[string]any, len(g.messages))
+	for _, m := range g.messages {
+		if m.MessageID == "" {
+			continue
+		}
+		var item map[string]any
+		b, _ := json.Marshal(m)
+		_ = json.Unmarshal(b, &item)
+		item["messageId"] = m.MessageID
+		item["groupId"] = g.info.GroupID
+		item["status"] = "PENDING"
+		item["statusCode"] = "2000"
+		item["dateCreated"] = g.info.DateCreated
+		list[m.MessageID] = item
+	}
+	return list
+}
+
+// recipients counts the group's recipients the way the API does, one per
+// entry of a message's ToList.
+func (g *dryRunGroup) recipients() int {
+	n := 0
+	for _, m := range g.messages {
+		n += max(len(m.ToList), 1)
+	}
+	return n
+}
+
+func (d *DryRun) nextID(kind string) string {
+	d.seq++
+	return fmt.Sprintf("DRYRUN-%s-%08d", kind, d.seq)
+}
+
+func (d *DryRun) newGroup() *dryRunGroup {
+	now := messages.FormatDate(time.Now())
+	id := d.nextID("G")
+	g := &dryRunGroup{info: messages.GroupInfo{ID: id, GroupID: id, Status: "PENDING", DateCreated: now, DateUpdated: now}}
+	d.groups[id] = g
+	return g
+}
+
+// dispatch marks the group as sent, or scheduled when date is set, and
+// assigns message IDs.
+func (d *DryRun) dispatch(g *dryRunGroup, date string) messages.DetailGroupMessageResponse {
+	now := messages.FormatDate(time.Now())
+	for i := range g.messages {
+		if g.messages[i].MessageID == "" {
+			g.messages[i].MessageID = d.nextID("M")
+		}
+	}
+	g.info.Count.Total = g.recipients()
+	g.info.Count.RegisteredSuccess = g.recipients()
+	g.info.Count.SentTotal = g.recipients()
+	g.info.Count.SentPending = g.recipients()
+	g.info.DateUpdated = now
+	if date != "" {
+		g.info.Status = "SCHEDULED"
+		g.info.ScheduledDate = date
+	} else {
+		g.info.Status = "SENDING"
+		g.info.DateSent = now
+	}
+	return d.detail(g)
+}
+
+func (d *DryRun) detail(g *dryRunGroup) messages.DetailGroupMessageResponse {
+	list := make([]messages.MessageListItem, 0, len(g.messages))
+	for _, m := range g.messages {
+		if m.MessageID == "" {
+			continue
+		}
+		list = append(list, messages.MessageListItem{
+			MessageID:     m.MessageID,
+			StatusCode:    "2000",
+			StatusMessage: "dry run: accepted",
+			CustomFields:  m.CustomFields,
+		})
+	}
+	return messages.DetailGroupMessageResponse{
+		GroupInfo:         g.info,
+		FailedMessageList: []messages.FailedMessage{},
+		MessageList:       list,
+		Status:            g.info.Status,
+		GroupID:           g.info.GroupID,
+		ScheduledDate:     g.info.ScheduledDate,
+		DateSent:          g.info.DateSent,
+		DateCreated:       g.info.DateCreated,
+		DateUpdated:       g.info.DateUpdated,
+	}
+}
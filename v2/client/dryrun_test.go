@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/blocks"
+	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestClient_WithDryRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("dry run reached the API: %s %s", r.Method, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	d := NewDryRun()
+	c := newClientWithBaseURL(ts.URL, "k", "s").WithDryRun(d)
+	ctx := context.Background()
+
+	res, err := c.Messages.Send(ctx, []messages.Message{
+		{To: "01011112222", From: "029302266", Text: "a"},
+		{ToList: []string{"01033334444", "01055556666"}, From: "029302266", Text: "b"},
+	}, messages.SendOptions{AppId: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GroupInfo.Count.Total != 3 || len(res.MessageList) != 2 || !strings.HasPrefix(res.MessageList[0].MessageID, "DRYRUN-M-") {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+	calls := d.Calls()
+	if len(calls) != 1 || !strings.Contains(string(calls[0].Payload), `"agent":{"sdkVersion":"go/2.0.0"`) || !strings.Contains(string(calls[0].Payload), `"appId":"app"`) {
+		t.Fatalf("unexpected capture: %+v", calls)
+	}
+
+	// local validation still applies
+	if _, err := c.Messages.Send(ctx, messages.Message{To: "01011112222", From: "029302266", Text: "a"}, messages.SendOptions{ScheduleAt: time.Now().Add(-time.Hour)}); !errors.Is(err, messages.ErrScheduleInPast) {
+		t.Fatalf("expected ErrScheduleInPast, got %v", err)
+	}
+
+	g, err := c.Groups.Create(ctx, groups.CreateGroupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Groups.AddMessages(ctx, g.GroupID, groups.AddGroupMessagesRequest{Messages: []messages.Message{{ToList: []string{"01011112222", "01033334444"}, From: "029302266", Text: "a"}}}); err != nil {
+		t.Fatal(err)
+	}
+	sched, err := c.Groups.ReserveAt(ctx, g.GroupID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched.GroupInfo.Status != "SCHEDULED" || sched.GroupInfo.Count.Total != 2 {
+		t.Fatalf("unexpected reserve response: %+v", sched.GroupInfo)
+	}
+	if _, err := c.Groups.Send(ctx, "G-unknown"); err == nil {
+		t.Fatal("expected unknown group error")
+	}
+	if len(d.Calls()) != 5 {
+		t.Fatalf("expected 5 captured calls, got %d", len(d.Calls()))
+	}
+}
+
+func TestClient_WithDryRun_Blocks(t *testing.T) {
+	var reached []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = append(reached, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"blockNumbers":[]}`))
+	}))
+	defer ts.Close()
+
+	d := NewDryRun()
+	c := newClientWithBaseURL(ts.URL, "k", "s").WithDryRun(d)
+	ctx := context.Background()
+
+	res, err := c.Blocks.Add(ctx, blocks.AddRequest{PhoneNumbers: []string{"01011112222"}, Memo: "opt-out"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.BlockNumbers) != 1 || !strings.HasPrefix(res.BlockNumbers[0].BlockNumberID, "DRYRUN-B-") || res.BlockNumbers[0].PhoneNumber != "01011112222" {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+	if _, err := c.Blocks.Remove(ctx, "BN1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Blocks.List(ctx, blocks.ListQuery{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reached) != 1 || reached[0] != "GET /iam/v1/block/numbers" {
+		t.Fatalf("only the read should reach the API, got %v", reached)
+	}
+	if calls := d.Calls(); len(calls) != 2 || calls[0].Method != http.MethodPost || calls[1].Path != "/iam/v1/block/numbers/BN1" {
+		t.Fatalf("unexpected capture: %+v", calls)
+	}
+}
+
+func TestClient_WithDryRun_Lookups(t *testing.T) {
+	var reached []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = append(reached, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"groupId":"G-real"}`))
+	}))
+	defer ts.Close()
+
+	d := NewDryRun()
+	c := newClientWithBaseURL(ts.URL, "k", "s").WithDryRun(d)
+	ctx := context.Background()
+
+	res, err := c.Messages.Send(ctx, messages.Message{To: "01011112222", From: "029302266", Text: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gid, mid := res.GroupInfo.GroupID, res.MessageList[0].MessageID
+
+	g, err := c.Groups.GetGroup(ctx, gid)
+	if err != nil || g.GroupInfo.Status != "SENDING" {
+		t.Fatalf("GetGroup: %+v %v", g, err)
+	}
+	list, err := c.Messages.List(ctx, messages.ListQuery{GroupID: gid})
+	if err != nil || len(list.MessageList) != 1 || list.MessageList[mid].To != "01011112222" {
+		t.Fatalf("List by group: %+v %v", list, err)
+	}
+	list, err = c.Messages.List(ctx, messages.ListQuery{MessageID: mid})
+	if err != nil || list.MessageList[mid].GroupID != gid {
+		t.Fatalf("List by message: %+v %v", list, err)
+	}
+	gm, err := c.Groups.ListMessages(ctx, gid, groups.ListMessagesQuery{})
+	if err != nil || len(gm.MessageList) != 1 {
+		t.Fatalf("ListMessages: %+v %v", gm, err)
+	}
+	if _, err := c.Groups.GetGroup(ctx, "DRYRUN-G-99999999"); err == nil {
+		t.Fatal("expected unknown dry-run group error")
+	}
+	if len(reached) != 0 {
+		t.Fatalf("dry-run lookups reached the API: %v", reached)
+	}
+
+	if _, err := c.Groups.GetGroup(ctx, "G-real"); err != nil || len(reached) != 1 {
+		t.Fatalf("real lookups should pass through: %v %v", reached, err)
+	}
+}
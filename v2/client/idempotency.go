@@ -0,0 +1,23 @@
+package client
+
+import "github.com/solapi/solapi-go/v2/idempotency"
+
+// WithIdempotencyStore returns a shallow copy of Client that keeps the
+// responses of calls made with an idempotency key in store, so repeating
+// such a call returns the first response instead of sending again.
+func (c *Client) WithIdempotencyStore(store idempotency.Store) *Client {
+	nc := *c
+	nc.idem = store
+	nc.initServices()
+	return &nc
+}
+
+// WithIdempotencyLookback returns a shallow copy of Client that searches
+// within l for an earlier attempt of a call made with an idempotency key
+// after a network error. See idempotency.Lookback for the defaults.
+func (c *Client) WithIdempotencyLookback(l idempotency.Lookback) *Client {
+	nc := *c
+	nc.lookback = l
+	nc.initServices()
+	return &nc
+}
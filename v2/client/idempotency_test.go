@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/idempotency"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestClient_WithIdempotencyStore(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"groupInfo":{"_id":"G1"}}`))
+	}))
+	defer ts.Close()
+
+	c := newClientWithBaseURL(ts.URL, "k", "s").WithIdempotencyStore(idempotency.NewMemoryStore(0))
+	msg := messages.Message{To: "01012345678", From: "029302266", Text: "hi"}
+	for i := 0; i < 2; i++ {
+		res, err := c.Messages.Send(context.Background(), msg, messages.SendOptions{IdempotencyKey: "k1"})
+		if err != nil || res.GroupInfo.ID != "G1" {
+			t.Fatalf("res=%+v err=%v", res, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("calls=%d, want 1", calls)
+	}
+}
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"github.com/solapi/solapi-go/v2/instrumentation"
+	"github.com/solapi/solapi-go/v2/internal/transport"
+)
+
+// WithInstrumentation returns a shallow copy of Client reporting a span,
+// latency and message volume for every API call to inst. A nil inst disables
+// instrumentation.
+func (c *Client) WithInstrumentation(inst instrumentation.Instrumentation) *Client {
+	return c.withOptions(func(o *transport.Options) {
+		o.Instrumentation = inst
+	})
+}
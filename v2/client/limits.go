@@ -0,0 +1,50 @@
+package client
+
+import "github.com/solapi/solapi-go/v2/internal/transport"
+
+// EndpointClass selects the endpoints an endpoint rate limit applies to.
+type EndpointClass = transport.EndpointClass
+
+const (
+	// EndpointSend covers sends and group mutations.
+	EndpointSend = transport.ClassSend
+	// EndpointList covers read-only calls such as message and group lists.
+	EndpointList = transport.ClassList
+	// EndpointUpload covers storage uploads.
+	EndpointUpload = transport.ClassUpload
+)
+
+// RateLimit configures client-side throttling. Zero fields disable the
+// corresponding limit. Waiting honors the request context: a call fails
+// immediately with context.DeadlineExceeded if its deadline would pass first.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained request rate.
+	RequestsPerSecond float64
+	// Burst is the number of requests allowed at once above the rate.
+	Burst int
+	// MaxInFlight caps concurrent requests.
+	MaxInFlight int
+	// Adaptive halves the rate whenever the API answers 429 or 503 and
+	// recovers it gradually on success.
+	Adaptive bool
+}
+
+func (l RateLimit) build() *transport.Limit {
+	return transport.NewLimit(l.RequestsPerSecond, l.Burst, l.MaxInFlight, l.Adaptive)
+}
+
+// WithRateLimit returns a shallow copy of Client whose requests are all
+// subject to l. Copies derived from the result share the same limiter.
+func (c *Client) WithRateLimit(l RateLimit) *Client {
+	return c.withOptions(func(o *transport.Options) {
+		o.Limit = l.build()
+	})
+}
+
+// WithEndpointRateLimit returns a shallow copy of Client applying l to the
+// given endpoint class, in addition to any client-wide limit.
+func (c *Client) WithEndpointRateLimit(class EndpointClass, l RateLimit) *Client {
+	return c.withOptions(func(o *transport.Options) {
+		o.EndpointLimits[class] = l.build()
+	})
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/groups"
+)
+
+func TestClient_WithEndpointRateLimit_AppliesToServices(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"groupId":"g1"}`))
+	}))
+	defer ts.Close()
+
+	base := newClientWithBaseURL(ts.URL, "k", "s")
+	c := base.WithEndpointRateLimit(EndpointSend, RateLimit{RequestsPerSecond: 0.1, Burst: 1})
+	if _, err := c.Groups.Create(context.Background(), groups.CreateGroupOptions{}); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.Groups.Create(ctx, groups.CreateGroupOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	// the original client is not limited
+	if _, err := base.Groups.Create(ctx, groups.CreateGroupOptions{}); err != nil {
+		t.Fatalf("base client: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"log/slog"
+
+	"github.com/solapi/solapi-go/v2/internal/transport"
+)
+
+// LogOptions tunes request logging. The zero value logs one record per call
+// and masks phone numbers and message text.
+type LogOptions struct {
+	// LogBodies adds redacted request and response bodies and request
+	// headers at debug level.
+	LogBodies bool
+	// ShowPhoneNumbers logs phone numbers unmasked.
+	ShowPhoneNumbers bool
+	// ShowText logs message text and subjects unmasked.
+	ShowText bool
+}
+
+// WithLogger returns a shallow copy of Client that logs every API call to l:
+// method, path, status, duration, attempts and error code at info level
+// (warn on failure), and each attempt and retry at debug level.
+// Authorization headers and API secrets are never logged. A nil l disables
+// logging.
+func (c *Client) WithLogger(l *slog.Logger, opts ...LogOptions) *Client {
+	var o LogOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	return c.withOptions(func(to *transport.Options) {
+		if l == nil {
+			to.Log = nil
+			return
+		}
+		to.Log = &transport.Logging{
+			Logger:           l,
+			LogBodies:        o.LogBodies,
+			ShowPhoneNumbers: o.ShowPhoneNumbers,
+			ShowText:         o.ShowText,
+		}
+	})
+}
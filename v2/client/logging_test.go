@@ -0,0 +1,38 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/groups"
+)
+
+func TestClient_WithLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"groupId":"g1"}`))
+	}))
+	defer ts.Close()
+
+	var out bytes.Buffer
+	base := newClientWithBaseURL(ts.URL, "k", "s")
+	c := base.WithLogger(slog.New(slog.NewTextHandler(&out, nil)))
+	if _, err := c.Groups.Create(context.Background(), groups.CreateGroupOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "msg=\"solapi call\" method=POST path=/messages/v4/groups status=200") {
+		t.Fatalf("unexpected log: %s", out.String())
+	}
+	out.Reset()
+	if _, err := base.Groups.Create(context.Background(), groups.CreateGroupOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("base client must not log: %s", out.String())
+	}
+}
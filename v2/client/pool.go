@@ -0,0 +1,338 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/credentials"
+	"github.com/solapi/solapi-go/v2/internal/ratelimit"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+var (
+	// ErrNoTenant is returned when the context carries no tenant key.
+	ErrNoTenant = errors.New("client: no tenant in context")
+	// ErrUnknownTenant is returned for tenant keys not registered in the pool.
+	ErrUnknownTenant = errors.New("client: unknown tenant")
+	// ErrSenderNotAllowed is returned when a message uses a sender number not
+	// configured for the tenant.
+	ErrSenderNotAllowed = errors.New("client: sender number not allowed for tenant")
+)
+
+// TenantConfig describes one account managed by a Pool.
+type TenantConfig struct {
+	APIKey    string
+	APISecret string
+	// Credentials overrides APIKey and APISecret when set.
+	Credentials credentials.Provider
+	// AppId is applied to sends that do not set one.
+	AppId string
+	// Senders lists the sender numbers the tenant may use, compared by their
+	// digits. The first one is applied to messages without From. An empty
+	// list allows any sender.
+	Senders []string
+	// RateLimit caps requests per second for the tenant; zero means no limit.
+	RateLimit float64
+	// Burst is the number of requests allowed above RateLimit at once.
+	Burst int
+}
+
+// TenantMetrics are cumulative counters for a tenant.
+type TenantMetrics struct {
+	Requests uint64
+	Errors   uint64
+	// MessagesSent counts the messages registered by send-many and group
+	// send calls, whether made by Pool.Send or through the tenant's client.
+	MessagesSent uint64
+	// Latency is the total time spent in HTTP round trips, including waits
+	// imposed by the rate limit.
+	Latency time.Duration
+}
+
+func (m TenantMetrics) add(o TenantMetrics) TenantMetrics {
+	m.Requests += o.Requests
+	m.Errors += o.Errors
+	m.MessagesSent += o.MessagesSent
+	m.Latency += o.Latency
+	return m
+}
+
+// PoolMetrics aggregates metrics across tenants.
+type PoolMetrics struct {
+	Tenants map[string]TenantMetrics
+	Total   TenantMetrics
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context routed to the named tenant by Pool.For.
+func WithTenant(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, name)
+}
+
+// TenantFromContext returns the tenant key stored by WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(tenantKey{}).(string)
+	return name, ok && name != ""
+}
+
+// Pool holds one Client per tenant. All clients share a single
+// http.RoundTripper so connections are pooled across tenants.
+type Pool struct {
+	baseURL   string
+	transport http.RoundTripper
+
+	mu      sync.RWMutex
+	tenants map[string]*tenant
+}
+
+type tenant struct {
+	client  *Client
+	cfg     TenantConfig
+	metrics tenantCounters
+}
+
+type tenantCounters struct {
+	requests, errors, sent atomic.Uint64
+	latency                atomic.Int64
+}
+
+// NewPool returns an empty pool sharing rt between tenants. A nil rt uses
+// http.DefaultTransport.
+func NewPool(rt http.RoundTripper) *Pool {
+	return newPoolWithBaseURL(defaultBaseURL, rt)
+}
+
+func newPoolWithBaseURL(baseURL string, rt http.RoundTripper) *Pool {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &Pool{baseURL: baseURL, transport: rt, tenants: map[string]*tenant{}}
+}
+
+// Add registers or replaces a tenant.
+func (p *Pool) Add(name string, cfg TenantConfig) error {
+	if name == "" {
+		return errors.New("client: tenant name is required")
+	}
+	creds := cfg.Credentials
+	if creds == nil {
+		if cfg.APIKey == "" || cfg.APISecret == "" {
+			return fmt.Errorf("client: tenant %q has no credentials", name)
+		}
+		creds = credentials.Static(cfg.APIKey, cfg.APISecret)
+	}
+	t := &tenant{cfg: cfg}
+	rt := &tenantTransport{base: p.transport, counters: &t.metrics}
+	if cfg.RateLimit > 0 {
+		rt.limiter = ratelimit.New(cfg.RateLimit, cfg.Burst)
+	}
+	t.client = newClientWithProvider(p.baseURL, creds).WithHTTPClient(&http.Client{Transport: rt})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tenants[name] = t
+	return nil
+}
+
+// Remove unregisters a tenant.
+func (p *Pool) Remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.tenants, name)
+}
+
+// Tenants returns the registered tenant names in sorted order.
+func (p *Pool) Tenants() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	names := make([]string, 0, len(p.tenants))
+	for name := range p.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Client returns the client of the named tenant.
+func (p *Pool) Client(name string) (*Client, error) {
+	t, err := p.tenant(name)
+	if err != nil {
+		return nil, err
+	}
+	return t.client, nil
+}
+
+// For returns the client of the tenant stored in ctx by WithTenant.
+func (p *Pool) For(ctx context.Context) (*Client, error) {
+	name, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+	return p.Client(name)
+}
+
+// Send routes a send to the tenant in ctx. It accepts the same inputs as
+// messages.Service.Send, applies the tenant's AppId and default sender, and
+// rejects senders the tenant is not configured for.
+func (p *Pool) Send(ctx context.Context, input any, opts ...messages.SendOptions) (messages.DetailGroupMessageResponse, error) {
+	name, ok := TenantFromContext(ctx)
+	if !ok {
+		return messages.DetailGroupMessageResponse{}, ErrNoTenant
+	}
+	t, err := p.tenant(name)
+	if err != nil {
+		return messages.DetailGroupMessageResponse{}, err
+	}
+	req, err := messages.NewSendRequest(input, opts...)
+	if err != nil {
+		return messages.DetailGroupMessageResponse{}, err
+	}
+	if req.AppId == "" {
+		req.AppId = t.cfg.AppId
+	}
+	msgs := make([]messages.Message, len(req.Messages))
+	for i, m := range req.Messages {
+		if m.From == "" && len(t.cfg.Senders) > 0 {
+			m.From = t.cfg.Senders[0]
+		}
+		if !t.allowsSender(m.From) {
+			return messages.DetailGroupMessageResponse{}, fmt.Errorf("%w: tenant %q, sender %q", ErrSenderNotAllowed, name, m.From)
+		}
+		msgs[i] = m
+	}
+	req.Messages = msgs
+
+	return t.client.Messages.SendManyDetail(ctx, req)
+}
+
+// Metrics returns a snapshot of per-tenant and total metrics.
+func (p *Pool) Metrics() PoolMetrics {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := PoolMetrics{Tenants: make(map[string]TenantMetrics, len(p.tenants))}
+	for name, t := range p.tenants {
+		m := TenantMetrics{
+			Requests:     t.metrics.requests.Load(),
+			Errors:       t.metrics.errors.Load(),
+			MessagesSent: t.metrics.sent.Load(),
+			Latency:      time.Duration(t.metrics.latency.Load()),
+		}
+		out.Tenants[name] = m
+		out.Total = out.Total.add(m)
+	}
+	return out
+}
+
+func (p *Pool) tenant(name string) (*tenant, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	t, ok := p.tenants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, name)
+	}
+	return t, nil
+}
+
+// allowsSender compares numbers by their digits, so "02-0000-0000" allows
+// "0200000000".
+func (t *tenant) allowsSender(from string) bool {
+	if len(t.cfg.Senders) == 0 {
+		return true
+	}
+	d := digitsOnly(from)
+	for _, s := range t.cfg.Senders {
+		if digitsOnly(s) == d {
+			return true
+		}
+	}
+	return false
+}
+
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// tenantTransport enforces a tenant's rate limit and records its metrics
+// before delegating to the shared transport.
+type tenantTransport struct {
+	base     http.RoundTripper
+	limiter  *ratelimit.Limiter
+	counters *tenantCounters
+}
+
+func (t *tenantTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	t.counters.requests.Add(1)
+	defer func() { t.counters.latency.Add(int64(time.Since(start))) }()
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		t.counters.errors.Add(1)
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode >= 400 {
+		t.counters.errors.Add(1)
+		return resp, err
+	}
+	if isSend(req) {
+		if err := t.countSent(resp); err != nil {
+			t.counters.errors.Add(1)
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// isSend reports whether req sends messages: a send-many call or the send of
+// a group.
+func isSend(req *http.Request) bool {
+	if req.Method != http.MethodPost {
+		return false
+	}
+	p := req.URL.Path
+	return strings.HasSuffix(p, "/messages/v4/send-many/detail") ||
+		strings.Contains(p, "/messages/v4/groups/") && strings.HasSuffix(p, "/send")
+}
+
+// countSent adds the messages registered according to the group count in
+// resp, leaving resp.Body readable.
+func (t *tenantTransport) countSent(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	var res struct {
+		GroupInfo struct {
+			Count messages.GroupCount `json:"count"`
+		} `json:"groupInfo"`
+	}
+	if json.Unmarshal(body, &res) != nil {
+		// not ours to judge; the service reports malformed responses
+		return nil
+	}
+	c := res.GroupInfo.Count
+	if registered := c.Total - c.RegisteredFailed; registered > 0 {
+		t.counters.sent.Add(uint64(registered))
+	}
+	return nil
+}
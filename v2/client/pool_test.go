@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestPool_RoutesByTenant(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]map[string]any{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "HMAC-SHA256 apiKey="), ",")[0]
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		seen[key] = body
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"groupInfo": map[string]any{"count": map[string]any{"total": 1}},
+		})
+	}))
+	defer ts.Close()
+
+	p := newPoolWithBaseURL(ts.URL, nil)
+	if err := p.Add("a", TenantConfig{APIKey: "ka", APISecret: "s", AppId: "app-a", Senders: []string{"0200000000"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Add("b", TenantConfig{APIKey: "kb", APISecret: "s", RateLimit: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithTenant(context.Background(), "a")
+	if _, err := p.Send(ctx, messages.Message{To: "010"}); err != nil {
+		t.Fatalf("send a: %v", err)
+	}
+	if _, err := p.Send(WithTenant(context.Background(), "b"), messages.Message{To: "010", From: "021"}); err != nil {
+		t.Fatalf("send b: %v", err)
+	}
+
+	a := seen["ka"]
+	if a == nil || a["agent"].(map[string]any)["appId"] != "app-a" {
+		t.Fatalf("tenant a app id not applied: %v", a)
+	}
+	if from := a["messages"].([]any)[0].(map[string]any)["from"]; from != "0200000000" {
+		t.Fatalf("default sender not applied: %v", from)
+	}
+	if seen["kb"] == nil {
+		t.Fatalf("tenant b not routed with its own key")
+	}
+
+	m := p.Metrics()
+	if m.Tenants["a"].Requests != 1 || m.Tenants["b"].Requests != 1 || m.Total.MessagesSent != 2 {
+		t.Fatalf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestPool_RejectsUnknownTenantAndSender(t *testing.T) {
+	p := newPoolWithBaseURL("http://invalid", nil)
+	_ = p.Add("a", TenantConfig{APIKey: "k", APISecret: "s", Senders: []string{"0200000000"}})
+
+	if _, err := p.Send(context.Background(), messages.Message{To: "010"}); !errors.Is(err, ErrNoTenant) {
+		t.Fatalf("expected ErrNoTenant, got %v", err)
+	}
+	if _, err := p.For(WithTenant(context.Background(), "zzz")); !errors.Is(err, ErrUnknownTenant) {
+		t.Fatalf("expected ErrUnknownTenant, got %v", err)
+	}
+	_, err := p.Send(WithTenant(context.Background(), "a"), messages.Message{To: "010", From: "0311111111"})
+	if !errors.Is(err, ErrSenderNotAllowed) {
+		t.Fatalf("expected ErrSenderNotAllowed, got %v", err)
+	}
+}
+
+func TestPool_SenderComparedByDigits(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+	p := newPoolWithBaseURL(ts.URL, nil)
+	_ = p.Add("a", TenantConfig{APIKey: "k", APISecret: "s", Senders: []string{"02-0000-0000"}})
+	if _, err := p.Send(WithTenant(context.Background(), "a"), messages.Message{To: "01012345678", From: "0200000000", Text: "hi"}); err != nil {
+		t.Fatalf("formatted sender should allow the plain number: %v", err)
+	}
+}
+
+func TestPool_CountsSendsThroughTenantClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/send-many/detail"):
+			w.Write([]byte(`{"groupInfo":{"count":{"total":3,"registeredFailed":1}}}`))
+		case strings.HasSuffix(r.URL.Path, "/send"):
+			w.Write([]byte(`{"groupInfo":{"count":{"total":5}}}`))
+		default:
+			w.Write([]byte(`{"groupInfo":{"count":{"total":100}}}`))
+		}
+	}))
+	defer ts.Close()
+
+	p := newPoolWithBaseURL(ts.URL, nil)
+	_ = p.Add("a", TenantConfig{APIKey: "k", APISecret: "s"})
+	ctx := WithTenant(context.Background(), "a")
+	c, err := p.For(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Messages.Send(ctx, []messages.Message{{To: "01011111111"}, {To: "01022222222"}, {To: "01033333333"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Groups.Send(ctx, "G1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Groups.GetGroup(ctx, "G1"); err != nil {
+		t.Fatal(err)
+	}
+	if m := p.Metrics().Tenants["a"]; m.MessagesSent != 7 || m.Requests != 3 {
+		t.Fatalf("unexpected metrics: %+v", m)
+	}
+}
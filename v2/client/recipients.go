@@ -0,0 +1,40 @@
+package client
+
+import (
+	"os"
+
+	"github.com/solapi/solapi-go/v2/blocks"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// EnvEnvironment names the deployment environment. WithRecipientPolicy is
+// a no-op when it is set to "production".
+const EnvEnvironment = "SOLAPI_ENV"
+
+// WithRecipientFilter returns a shallow copy of Client that runs f on every
+// recipient of Messages sends and Groups.AddMessages, after any filters
+// added before.
+func (c *Client) WithRecipientFilter(f messages.RecipientFilter) *Client {
+	nc := *c
+	nc.filters = append(append([]messages.RecipientFilter(nil), c.filters...), f)
+	nc.initServices()
+	return &nc
+}
+
+// WithRecipientPolicy returns a shallow copy of Client enforcing p, so that
+// non-production environments can only reach allowlisted numbers. When the
+// SOLAPI_ENV environment variable is "production" the receiver is returned
+// unchanged, which lets the same configuration ship everywhere.
+func (c *Client) WithRecipientPolicy(p messages.RecipientPolicy) *Client {
+	if os.Getenv(EnvEnvironment) == "production" {
+		return c
+	}
+	return c.WithRecipientFilter(&p)
+}
+
+// WithSuppression returns a shallow copy of Client that drops recipients on
+// s from sends and group appends. Dropped recipients are reported in the
+// Filtered field of the responses.
+func (c *Client) WithSuppression(s *blocks.Suppression) *Client {
+	return c.WithRecipientFilter(s)
+}
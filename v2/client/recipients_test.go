@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestClient_WithRecipientPolicy(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	policy := messages.RecipientPolicy{AllowPrefixes: []string{"0100000"}}
+	c := newClientWithBaseURL(ts.URL, "k", "s").WithRecipientPolicy(policy)
+	ctx := context.Background()
+	msg := messages.Message{To: "01012345678", From: "029302266", Text: "hi"}
+	if _, err := c.Messages.Send(ctx, msg); !errors.Is(err, messages.ErrRecipientRejected) {
+		t.Fatalf("expected rejection, got %v", err)
+	}
+	if _, err := c.Groups.AddMessages(ctx, "G1", groups.AddGroupMessagesRequest{Messages: []messages.Message{msg}}); !errors.Is(err, messages.ErrRecipientRejected) {
+		t.Fatalf("expected rejection on group append, got %v", err)
+	}
+	drop := newClientWithBaseURL(ts.URL, "k", "s").WithRecipientPolicy(messages.RecipientPolicy{AllowPrefixes: []string{"0100000"}, Mode: messages.PolicyDrop})
+	res, err := drop.Groups.AddMessages(ctx, "G1", groups.AddGroupMessagesRequest{Messages: []messages.Message{msg}})
+	if !errors.Is(err, messages.ErrNoRecipients) || len(res.Filtered) != 1 {
+		t.Fatalf("expected ErrNoRecipients with the report, got %+v %v", res, err)
+	}
+	if calls != 0 {
+		t.Fatalf("rejected sends must not reach the API")
+	}
+
+	t.Setenv(EnvEnvironment, "production")
+	prod := newClientWithBaseURL(ts.URL, "k", "s").WithRecipientPolicy(policy)
+	if _, err := prod.Groups.AddMessages(ctx, "G1", groups.AddGroupMessagesRequest{Messages: []messages.Message{msg}}); err != nil {
+		t.Fatalf("policy must be off in production: %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+const groupsUsage = `usage: solapi groups <command> [arguments]
+
+commands:
+  create                      create an empty group
+  add <group-id> <kind>       add a message of kind sms, lms, mms, alimtalk, voice or fax
+  send <group-id>             send the group now
+  schedule <group-id> <time>  send the group at time
+  cancel <group-id>           cancel a scheduled send
+  show <group-id>             show the group
+  rm <group-id>               delete the group
+`
+
+func (a *app) groups(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(a.stderr, groupsUsage)
+		return errUsage
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "create":
+		return a.groupsCreate(ctx, args)
+	case "add":
+		return a.groupsAdd(ctx, args)
+	case "send", "cancel", "show", "rm":
+		return a.groupsSimple(ctx, cmd, args)
+	case "schedule":
+		return a.groupsSchedule(ctx, args)
+	}
+	fmt.Fprintf(a.stderr, "solapi: unknown groups command %q\n", cmd)
+	fmt.Fprint(a.stderr, groupsUsage)
+	return errUsage
+}
+
+func (a *app) groupsCreate(ctx context.Context, args []string) error {
+	fs := a.flags("groups create", "[flags]")
+	var opt groups.CreateGroupOptions
+	fields := mapFlag{}
+	fs.StringVar(&opt.AppId, "app-id", "", "app ID")
+	fs.BoolVar(&opt.AllowDuplicates, "allow-duplicates", false, "allow the same recipient twice")
+	fs.Var(fields, "field", "custom field `key=value`, repeated")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		opt.CustomFields = fields
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	res, err := c.Groups.Create(ctx, opt)
+	if err != nil {
+		return err
+	}
+	return a.output(res, func() error {
+		_, err := fmt.Fprintln(a.stdout, res.GroupID)
+		return err
+	})
+}
+
+func (a *app) groupsAdd(ctx context.Context, args []string) error {
+	fs := a.flags("groups add", "<group-id> <sms|lms|mms|alimtalk|voice|fax> -to number [flags]")
+	var mf messageFlags
+	mf.register(fs)
+	allowDup := fs.Bool("allow-duplicates", false, "allow the same recipient twice")
+	pos, err := parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	m, err := mf.message(ctx, c, pos[1])
+	if err != nil {
+		return err
+	}
+	req := groups.AddGroupMessagesRequest{Messages: []messages.Message{m}}
+	if *allowDup {
+		req.AllowDuplicates = allowDup
+	}
+	res, err := c.Groups.AddMessages(ctx, pos[0], req)
+	if err != nil {
+		return err
+	}
+	return a.output(res, func() error {
+		count := res.GroupInfo.Count
+		if err := a.printFields(
+			"Group", pos[0],
+			"Total", strconv.Itoa(count.Total),
+			"Failed", strconv.Itoa(len(res.FailedMessageList)),
+		); err != nil {
+			return err
+		}
+		if len(res.FailedMessageList) == 0 {
+			return nil
+		}
+		fmt.Fprintln(a.stdout)
+		return a.printFailures(res.FailedMessageList)
+	})
+}
+
+func (a *app) groupsSchedule(ctx context.Context, args []string) error {
+	fs := a.flags("groups schedule", "<group-id> <time>")
+	pos, err := parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	t, err := parseTime(pos[1])
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	res, err := c.Groups.ReserveAt(ctx, pos[0], t)
+	if err != nil {
+		return err
+	}
+	return a.output(res, func() error { return a.printGroupInfo(pos[0], res.GroupInfo) })
+}
+
+// groupsSimple runs the commands that only take a group ID.
+func (a *app) groupsSimple(ctx context.Context, cmd string, args []string) error {
+	fs := a.flags("groups "+cmd, "<group-id>")
+	pos, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	id := pos[0]
+	var res any
+	var info messages.GroupInfo
+	switch cmd {
+	case "send":
+		r, err := c.Groups.Send(ctx, id)
+		if err != nil {
+			return err
+		}
+		res, info = r, r.GroupInfo
+	case "cancel":
+		r, err := c.Groups.CancelReservation(ctx, id)
+		if err != nil {
+			return err
+		}
+		res, info = r, r.GroupInfo
+	case "show":
+		r, err := c.Groups.GetGroup(ctx, id)
+		if err != nil {
+			return err
+		}
+		res, info = r, r.GroupInfo
+	case "rm":
+		r, err := c.Groups.RemoveGroup(ctx, id)
+		if err != nil {
+			return err
+		}
+		res, info = r, r.GroupInfo
+	}
+	return a.output(res, func() error { return a.printGroupInfo(id, info) })
+}
+
+func (a *app) printGroupInfo(id string, g messages.GroupInfo) error {
+	return a.printFields(
+		"Group", id,
+		"Status", g.Status,
+		"Total", strconv.Itoa(g.Count.Total),
+		"Registered", strconv.Itoa(g.Count.RegisteredSuccess),
+		"Sent", strconv.Itoa(g.Count.SentSuccess),
+		"Failed", strconv.Itoa(g.Count.SentFailed+g.Count.RegisteredFailed),
+		"Pending", strconv.Itoa(g.Count.SentPending),
+		"Scheduled", g.ScheduledDate,
+		"Created", g.DateCreated,
+	)
+}
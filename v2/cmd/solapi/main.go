@@ -0,0 +1,161 @@
+// Command solapi sends messages and inspects the SOLAPI account from the
+// shell.
+//
+// Usage:
+//
+//	solapi [-json] [-profile name] <command> [arguments]
+//
+// Commands:
+//
+//	send <sms|lms|mms|alimtalk|voice|fax>   send a message
+//	messages list                           list sent messages
+//	messages tail -group id                 follow the status of a group
+//	groups create|add|send|schedule|cancel|show|rm
+//	storage upload <file>                   upload an image or fax file
+//	balance                                 show the prepaid balance
+//
+// Credentials, the base URL, the app ID and the default sender number come
+// from a profile in ~/.config/solapi/config, combined with SOLAPI_API_KEY,
+// SOLAPI_API_SECRET and the other variables as described in package config.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/solapi/solapi-go/v2/client"
+)
+
+const usage = `usage: solapi [-json] [-profile name] <command> [arguments]
+
+commands:
+  send <sms|lms|mms|alimtalk|voice|fax>   send a message
+  messages list                           list sent messages
+  messages tail -group id                 follow the status of a group
+  groups <create|add|send|schedule|cancel|show|rm>
+  storage upload <file>                   upload an image or fax file
+  balance                                 show the prepaid balance
+
+Run "solapi <command> -h" for the flags of a command.
+`
+
+// errUsage is returned for invalid invocations; the usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	a := &app{stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(a.run(ctx, os.Args[1:]))
+}
+
+// app holds the global flags and the output streams of one invocation.
+type app struct {
+	stdout, stderr io.Writer
+	json           bool
+	profile        string
+
+	// httpClient replaces the client's transport in tests.
+	httpClient *http.Client
+}
+
+// run executes the command line args and returns the exit code.
+func (a *app) run(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("solapi", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() { fmt.Fprint(a.stderr, usage) }
+	fs.BoolVar(&a.json, "json", false, "print JSON instead of tables")
+	fs.StringVar(&a.profile, "profile", "", "config profile `name` (default $SOLAPI_PROFILE or default)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	return a.exit(a.dispatch(ctx, fs.Args()))
+}
+
+func (a *app) exit(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return 2
+	}
+	fmt.Fprintln(a.stderr, "solapi:", err)
+	return 1
+}
+
+func (a *app) dispatch(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(a.stderr, usage)
+		return errUsage
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "send":
+		return a.send(ctx, args)
+	case "messages":
+		return a.messages(ctx, args)
+	case "groups":
+		return a.groups(ctx, args)
+	case "storage":
+		return a.storage(ctx, args)
+	case "balance":
+		return a.balance(ctx, args)
+	case "help":
+		fmt.Fprint(a.stdout, usage)
+		return nil
+	}
+	fmt.Fprintf(a.stderr, "solapi: unknown command %q\n", cmd)
+	fmt.Fprint(a.stderr, usage)
+	return errUsage
+}
+
+// client builds the API client from the selected config profile.
+func (a *app) client() (*client.Client, error) {
+	c, err := client.NewFromConfig(a.profile)
+	if err != nil {
+		return nil, err
+	}
+	if a.httpClient != nil {
+		c = c.WithHTTPClient(a.httpClient)
+	}
+	return c, nil
+}
+
+// flags returns a flag set for a subcommand that also accepts -json.
+func (a *app) flags(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: solapi %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	fs.BoolVar(&a.json, "json", a.json, "print JSON instead of tables")
+	return fs
+}
+
+// parse parses flags that may appear before, between or after the
+// positional arguments, and checks that want positional arguments remain.
+func parse(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(pos) != want {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return pos, nil
+}
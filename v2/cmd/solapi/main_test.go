@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// redirect sends every request to the test server.
+type redirect struct{ target *url.URL }
+
+func (r redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = r.target.Scheme, r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestMain isolates the tests from the user's config and environment.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "solapi-cli")
+	if err != nil {
+		panic(err)
+	}
+	for env, v := range map[string]string{
+		"SOLAPI_CONFIG":     "",
+		"SOLAPI_PROFILE":    "",
+		"SOLAPI_API_KEY":    "key",
+		"SOLAPI_API_SECRET": "secret",
+		"SOLAPI_BASE_URL":   "",
+		"SOLAPI_APP_ID":     "",
+		"SOLAPI_FROM":       "",
+		"XDG_CONFIG_HOME":   dir,
+		"HOME":              dir,
+	} {
+		os.Setenv(env, v)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func runCLI(t *testing.T, h http.HandlerFunc, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	u, _ := url.Parse(ts.URL)
+	var out, errOut bytes.Buffer
+	a := &app{stdout: &out, stderr: &errOut, httpClient: &http.Client{Transport: redirect{u}}}
+	code = a.run(context.Background(), args)
+	return out.String(), errOut.String(), code
+}
+
+func TestSendSMS(t *testing.T) {
+	var body struct {
+		Messages []map[string]any `json:"messages"`
+	}
+	out, errOut, code := runCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages/v4/send-many/detail" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"groupInfo":{"_id":"G1","status":"SENDING","count":{"registeredSuccess":2}},"failedMessageList":[]}`))
+	}, "send", "sms", "-to", "01011112222,01033334444", "-from", "029302266", "-text", "hi")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	to, _ := body.Messages[0]["to"].([]any)
+	if len(body.Messages) != 1 || len(to) != 2 || to[1] != "01033334444" || body.Messages[0]["type"] != "SMS" {
+		t.Fatalf("request: %+v", body.Messages)
+	}
+	if !strings.Contains(out, "G1") || !strings.Contains(out, "Registered:  2") {
+		t.Fatalf("output:\n%s", out)
+	}
+}
+
+func TestSendAlimTalkJSON(t *testing.T) {
+	var body struct {
+		Messages []struct {
+			Type         string `json:"type"`
+			KakaoOptions struct {
+				PfID      string            `json:"pfId"`
+				Variables map[string]string `json:"variables"`
+			} `json:"kakaoOptions"`
+		} `json:"messages"`
+	}
+	out, errOut, code := runCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"groupInfo":{"_id":"G1"}}`))
+	}, "-json", "send", "alimtalk", "-to", "01011112222", "-from", "029302266", "-pfid", "PF", "-template-id", "T", "-var", "#{name}=홍길동")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	m := body.Messages[0]
+	if m.Type != "ATA" || m.KakaoOptions.PfID != "PF" || m.KakaoOptions.Variables["#{name}"] != "홍길동" {
+		t.Fatalf("request: %+v", m)
+	}
+	var res map[string]any
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+}
+
+func TestSendMMSUploadsImage(t *testing.T) {
+	img := filepath.Join(t.TempDir(), "a.jpg")
+	os.WriteFile(img, []byte("jpeg"), 0o600)
+	var imageID string
+	_, errOut, code := runCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/storage/v1/files":
+			w.Write([]byte(`{"fileId":"F1"}`))
+		default:
+			var body struct {
+				Messages []struct {
+					ImageID string `json:"imageId"`
+				} `json:"messages"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			imageID = body.Messages[0].ImageID
+			w.Write([]byte(`{}`))
+		}
+	}, "send", "mms", "-to", "01011112222", "-from", "029302266", "-image", img, "-subject", "s")
+	if code != 0 || imageID != "F1" {
+		t.Fatalf("exit %d imageId %q: %s", code, imageID, errOut)
+	}
+}
+
+func TestMessagesList(t *testing.T) {
+	out, errOut, code := runCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("groupId") != "G1" || q.Get("type") != "SMS,LMS" || q.Get("limit") != "5" {
+			t.Errorf("query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"messageList":{
+			"M1":{"to":"01011112222","type":"SMS","status":"COMPLETE","dateCreated":"2026-01-01T00:00:00Z"},
+			"M2":{"to":"01033334444","type":"LMS","status":"PENDING","dateCreated":"2026-01-02T00:00:00Z"}},
+			"nextKey":"M0"}`))
+	}, "messages", "list", "-group-id", "G1", "-type", "SMS", "-type", "LMS", "-limit", "5")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if !strings.HasPrefix(lines[0], "MESSAGE ID") || !strings.HasPrefix(lines[1], "M2") || !strings.HasPrefix(lines[2], "M1") {
+		t.Fatalf("output:\n%s", out)
+	}
+	if !strings.Contains(out, "-start-key M0") {
+		t.Fatalf("missing next page hint:\n%s", out)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	var calls []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"groupId":"G1","groupInfo":{"status":"PENDING","count":{"total":1}}}`))
+	}
+	for _, args := range [][]string{
+		{"groups", "create", "-field", "batch=1"},
+		{"groups", "add", "G1", "sms", "-to", "01011112222", "-from", "029302266", "-text", "hi"},
+		{"groups", "schedule", "G1", time.Now().Add(24 * time.Hour).Format(time.RFC3339)},
+		{"groups", "cancel", "G1"},
+		{"groups", "show", "G1"},
+		{"groups", "send", "G1"},
+		{"groups", "rm", "G1"},
+	} {
+		if _, errOut, code := runCLI(t, h, args...); code != 0 {
+			t.Fatalf("%v: exit %d: %s", args, code, errOut)
+		}
+	}
+	want := []string{
+		"POST /messages/v4/groups",
+		"PUT /messages/v4/groups/G1/messages",
+		"POST /messages/v4/groups/G1/schedule",
+		"DELETE /messages/v4/groups/G1/schedule",
+		"GET /messages/v4/groups/G1",
+		"POST /messages/v4/groups/G1/send",
+		"DELETE /messages/v4/groups/G1",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls:\n%s", strings.Join(calls, "\n"))
+	}
+}
+
+func TestBalance(t *testing.T) {
+	out, _, code := runCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"balance":1500.5,"point":20}`))
+	}, "balance")
+	if code != 0 || !strings.Contains(out, "1500.5") || !strings.Contains(out, "20") {
+		t.Fatalf("exit %d:\n%s", code, out)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) { t.Errorf("unexpected request %s", r.URL) }
+	for _, args := range [][]string{
+		{},
+		{"bogus"},
+		{"groups", "send"},
+		{"send"},
+	} {
+		if _, _, code := runCLI(t, h, args...); code != 2 {
+			t.Errorf("%v: exit %d, want 2", args, code)
+		}
+	}
+	if _, errOut, code := runCLI(t, h, "send", "sms", "-text", "hi"); code != 1 || !strings.Contains(errOut, "-to") {
+		t.Errorf("missing -to: exit %d %q", code, errOut)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte("[staging]\napi_key = K\napi_secret = S\nfrom = 0212345678\n"), 0o600)
+	var from, auth string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				From string `json:"from"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		from, auth = body.Messages[0].From, r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}
+	t.Setenv("SOLAPI_CONFIG", path)
+	_, errOut, code := runCLI(t, h, "-profile", "staging", "send", "sms", "-to", "01011112222", "-text", "hi")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	if from != "0212345678" || !strings.Contains(auth, "apiKey=K,") {
+		t.Fatalf("from %q, authorization %q", from, auth)
+	}
+
+	if _, errOut, code := runCLI(t, h, "-profile", "prod", "balance"); code != 1 || !strings.Contains(errOut, "profile not found") {
+		t.Fatalf("unknown profile: exit %d %q", code, errOut)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func (a *app) messages(ctx context.Context, args []string) error {
+	if len(args) > 0 && args[0] == "tail" {
+		return a.messagesTail(ctx, args[1:])
+	}
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(a.stderr, "usage: solapi messages list|tail [flags]")
+		return errUsage
+	}
+	fs := a.flags("messages list", "[flags]")
+	var q messages.ListQuery
+	var types listFlag
+	var start, end string
+	fs.StringVar(&q.MessageID, "message-id", "", "message ID")
+	fs.StringVar(&q.GroupID, "group-id", "", "group ID")
+	fs.StringVar(&q.To, "to", "", "recipient number")
+	fs.StringVar(&q.From, "from", "", "sender number")
+	fs.Var(&types, "type", "message `types`, e.g. SMS,LMS")
+	fs.StringVar(&q.DateType, "date-type", "", "date the range applies to: CREATED or UPDATED")
+	fs.StringVar(&start, "start", "", "range start `time` (RFC 3339 or 2006-01-02 15:04)")
+	fs.StringVar(&end, "end", "", "range end `time`")
+	fs.StringVar(&q.StartKey, "start-key", "", "page key from a previous listing")
+	fs.IntVar(&q.Limit, "limit", 20, "maximum number of messages")
+	if _, err := parse(fs, args[1:], 0); err != nil {
+		return err
+	}
+	q.TypeIn = types
+	var err error
+	if start != "" {
+		if q.StartAt, err = parseTime(start); err != nil {
+			return err
+		}
+	}
+	if end != "" {
+		if q.EndAt, err = parseTime(end); err != nil {
+			return err
+		}
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	res, err := c.Messages.List(ctx, q)
+	if err != nil {
+		return err
+	}
+	return a.output(res, func() error {
+		if err := a.printMessages(res.MessageList); err != nil {
+			return err
+		}
+		if res.NextKey != "" {
+			fmt.Fprintf(a.stdout, "\nnext page: -start-key %s\n", res.NextKey)
+		}
+		return nil
+	})
+}
+
+// printMessages lists messages newest first.
+func (a *app) printMessages(list map[string]messages.Message) error {
+	ids := make([]string, 0, len(list))
+	for id := range list {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		mi, mj := list[ids[i]], list[ids[j]]
+		if mi.DateCreated != mj.DateCreated {
+			return mi.DateCreated > mj.DateCreated
+		}
+		return ids[i] < ids[j]
+	})
+	rows := make([][]string, len(ids))
+	for i, id := range ids {
+		m := list[id]
+		rows[i] = []string{id, m.Type, m.To, m.From, m.Status, m.StatusCode, m.DateCreated}
+	}
+	return a.printTable([]string{"MESSAGE ID", "TYPE", "TO", "FROM", "STATUS", "CODE", "CREATED"}, rows)
+}
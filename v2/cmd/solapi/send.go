@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/client"
+	"github.com/solapi/solapi-go/v2/messages"
+	"github.com/solapi/solapi-go/v2/storages"
+)
+
+// messageFlags are the flags describing a message, shared by send and
+// groups add.
+type messageFlags struct {
+	to         listFlag
+	from       string
+	text       string
+	subject    string
+	image      string
+	pfID       string
+	templateID string
+	vars       mapFlag
+	voiceType  string
+	faxFiles   listFlag
+}
+
+func (f *messageFlags) register(fs *flag.FlagSet) {
+	f.vars = mapFlag{}
+	fs.Var(&f.to, "to", "recipient `numbers`, comma-separated or repeated")
+	fs.StringVar(&f.from, "from", "", "registered sender `number` (default from the profile)")
+	fs.StringVar(&f.text, "text", "", "message text")
+	fs.StringVar(&f.subject, "subject", "", "LMS/MMS subject")
+	fs.StringVar(&f.image, "image", "", "MMS image `file`")
+	fs.StringVar(&f.pfID, "pfid", "", "AlimTalk channel ID")
+	fs.StringVar(&f.templateID, "template-id", "", "AlimTalk template ID")
+	fs.Var(f.vars, "var", "AlimTalk variable `#{name}=value`, repeated")
+	fs.StringVar(&f.voiceType, "voice-type", "FEMALE", "voice type: FEMALE or MALE")
+	fs.Var(&f.faxFiles, "fax-file", "fax `file`, repeated")
+}
+
+// kinds maps send kinds to API message types.
+var kinds = map[string]string{
+	"sms":      "SMS",
+	"lms":      "LMS",
+	"mms":      "MMS",
+	"alimtalk": "ATA",
+	"voice":    "VOICE",
+	"fax":      "FAX",
+}
+
+// message builds the message of the given kind, uploading files as needed.
+func (f *messageFlags) message(ctx context.Context, c *client.Client, kind string) (messages.Message, error) {
+	typ, ok := kinds[kind]
+	if !ok {
+		return messages.Message{}, fmt.Errorf("unknown message kind %q", kind)
+	}
+	if len(f.to) == 0 {
+		return messages.Message{}, errors.New("-to is required")
+	}
+	m := messages.Message{From: f.from, Text: f.text, Subject: f.subject, Type: typ}
+	if len(f.to) == 1 {
+		m.To = f.to[0]
+	} else {
+		m.ToList = f.to
+	}
+	switch kind {
+	case "sms", "lms", "voice":
+		if f.text == "" {
+			return m, errors.New("-text is required")
+		}
+		if kind == "voice" {
+			m.VoiceOptions = &messages.VoiceOptions{VoiceType: f.voiceType}
+		}
+	case "mms":
+		if f.image == "" {
+			return m, errors.New("-image is required")
+		}
+		file, err := upload(ctx, c, f.image, "MMS", "")
+		if err != nil {
+			return m, err
+		}
+		m.ImageID = file.FileID
+	case "alimtalk":
+		if f.pfID == "" || f.templateID == "" {
+			return m, errors.New("-pfid and -template-id are required")
+		}
+		m.KakaoOptions = &messages.KakaoOptions{PfID: f.pfID, TemplateID: f.templateID}
+		if len(f.vars) > 0 {
+			m.KakaoOptions.Variables = f.vars
+		}
+	case "fax":
+		if len(f.faxFiles) == 0 {
+			return m, errors.New("-fax-file is required")
+		}
+		m.FaxOptions = &messages.FaxOptions{}
+		for _, path := range f.faxFiles {
+			file, err := upload(ctx, c, path, "FAX", "")
+			if err != nil {
+				return m, err
+			}
+			m.FaxOptions.FileIDs = append(m.FaxOptions.FileIDs, file.FileID)
+		}
+	}
+	return m, nil
+}
+
+func upload(ctx context.Context, c *client.Client, path, typ, link string) (storages.UploadFileResponse, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return storages.UploadFileResponse{}, err
+	}
+	res, err := c.Storages.Upload(ctx, storages.UploadFileRequest{
+		File: base64.StdEncoding.EncodeToString(b),
+		Name: filepath.Base(path),
+		Type: typ,
+		Link: link,
+	})
+	if err != nil {
+		return res, fmt.Errorf("upload %s: %w", path, err)
+	}
+	return res, nil
+}
+
+func (a *app) send(ctx context.Context, args []string) error {
+	fs := a.flags("send", "<sms|lms|mms|alimtalk|voice|fax> -to number [flags]")
+	var mf messageFlags
+	mf.register(fs)
+	schedule := fs.String("schedule", "", "send at `time` (RFC 3339 or 2006-01-02 15:04)")
+	appID := fs.String("app-id", "", "app ID")
+	allowDup := fs.Bool("allow-duplicates", false, "allow the same recipient twice")
+	pos, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	m, err := mf.message(ctx, c, pos[0])
+	if err != nil {
+		return err
+	}
+	opt := messages.SendOptions{AppId: *appID}
+	if *allowDup {
+		opt.AllowDuplicates = allowDup
+	}
+	if *schedule != "" {
+		if opt.ScheduleAt, err = parseTime(*schedule); err != nil {
+			return err
+		}
+	}
+	res, err := c.Messages.Send(ctx, m, opt)
+	if err != nil {
+		return err
+	}
+	return a.output(res, func() error { return a.printSendResult(res) })
+}
+
+func (a *app) printSendResult(res messages.DetailGroupMessageResponse) error {
+	id := res.GroupInfo.ID
+	if id == "" {
+		id = res.GroupID
+	}
+	count := res.GroupInfo.Count
+	if err := a.printFields(
+		"Group", id,
+		"Status", res.GroupInfo.Status,
+		"Registered", strconv.Itoa(count.RegisteredSuccess),
+		"Failed", strconv.Itoa(len(res.FailedMessageList)),
+	); err != nil {
+		return err
+	}
+	if len(res.FailedMessageList) == 0 {
+		return nil
+	}
+	fmt.Fprintln(a.stdout)
+	return a.printFailures(res.FailedMessageList)
+}
+
+func (a *app) printFailures(failed []messages.FailedMessage) error {
+	rows := make([][]string, len(failed))
+	for i, f := range failed {
+		rows[i] = []string{f.To, f.StatusCode, f.StatusMessage}
+	}
+	return a.printTable([]string{"TO", "CODE", "REASON"}, rows)
+}
+
+// parseTime accepts RFC 3339 or "2006-01-02 15:04" in local time.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(s), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339 or 2006-01-02 15:04", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+func (a *app) storage(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "upload" {
+		fmt.Fprintln(a.stderr, "usage: solapi storage upload [-type MMS|FAX|KAKAO] <file>")
+		return errUsage
+	}
+	fs := a.flags("storage upload", "[flags] <file>")
+	typ := fs.String("type", "MMS", "file type: MMS, FAX or KAKAO")
+	link := fs.String("link", "", "link opened from a Kakao image")
+	pos, err := parse(fs, args[1:], 1)
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	res, err := upload(ctx, c, pos[0], *typ, *link)
+	if err != nil {
+		return err
+	}
+	return a.output(res, func() error {
+		_, err := fmt.Fprintln(a.stdout, res.FileID)
+		return err
+	})
+}
+
+func (a *app) balance(ctx context.Context, args []string) error {
+	fs := a.flags("balance", "")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	res, err := c.Cash.Balance(ctx)
+	if err != nil {
+		return err
+	}
+	return a.output(res, func() error {
+		return a.printFields(
+			"Balance", strconv.FormatFloat(res.Balance, 'f', -1, 64),
+			"Point", strconv.FormatFloat(res.Point, 'f', -1, 64),
+		)
+	})
+}
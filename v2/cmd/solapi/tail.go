@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/client"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// tailPageSize is the page size used when polling a group's messages.
+const tailPageSize = 500
+
+// tailOverlap widens each incremental query so updates stamped in the same
+// second as the previous poll are not missed.
+const tailOverlap = 2 * time.Second
+
+// finalGroupStatuses are the group statuses after which no message changes.
+var finalGroupStatuses = map[string]bool{"COMPLETE": true, "FAILED": true, "DELETED": true}
+
+// tailer tracks the last seen status of every message of a group.
+type tailer struct {
+	c       *client.Client
+	groupID string
+	status  map[string]messages.Message
+	since   time.Time
+}
+
+// tailChange is one status transition of a message.
+type tailChange struct {
+	MessageID      string `json:"messageId"`
+	To             string `json:"to"`
+	Status         string `json:"status"`
+	StatusCode     string `json:"statusCode"`
+	PrevStatus     string `json:"previousStatus,omitempty"`
+	PrevStatusCode string `json:"previousStatusCode,omitempty"`
+	DateUpdated    string `json:"dateUpdated,omitempty"`
+}
+
+// tailEvent is printed for every poll that saw a change.
+type tailEvent struct {
+	Time        time.Time           `json:"time"`
+	GroupStatus string              `json:"groupStatus"`
+	Changes     []tailChange        `json:"changes"`
+	Count       messages.GroupCount `json:"count"`
+}
+
+func (a *app) messagesTail(ctx context.Context, args []string) error {
+	fs := a.flags("messages tail", "-group id [flags]")
+	groupID := fs.String("group", "", "group ID to follow")
+	interval := fs.Duration("interval", 2*time.Second, "polling interval")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *groupID == "" {
+		fs.Usage()
+		return errUsage
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	t := &tailer{c: c, groupID: *groupID, status: map[string]messages.Message{}}
+	groupStatus := ""
+	for {
+		// The group status is read before the messages so the last poll
+		// after completion includes every final message status.
+		g, err := c.Groups.GetGroup(ctx, t.groupID)
+		if err != nil {
+			return tailErr(ctx, err)
+		}
+		changes, err := t.poll(ctx)
+		if err != nil {
+			return tailErr(ctx, err)
+		}
+		if len(changes) > 0 || g.GroupInfo.Status != groupStatus {
+			groupStatus = g.GroupInfo.Status
+			if err := a.printTail(tailEvent{Time: time.Now(), GroupStatus: groupStatus, Changes: changes, Count: t.count()}); err != nil {
+				return err
+			}
+		}
+		if finalGroupStatuses[groupStatus] {
+			return nil
+		}
+		timer := time.NewTimer(*interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// tailErr treats errors caused by Ctrl-C as a normal exit.
+func tailErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil
+	}
+	return err
+}
+
+// poll lists the messages updated since the previous poll and returns the
+// ones whose status changed, oldest update first.
+func (t *tailer) poll(ctx context.Context) ([]tailChange, error) {
+	q := messages.ListQuery{GroupID: t.groupID, Limit: tailPageSize}
+	if !t.since.IsZero() {
+		q.DateType = "UPDATED"
+		q.StartAt = t.since.Add(-tailOverlap)
+	}
+	var changes []tailChange
+	for {
+		res, err := t.c.Messages.List(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		for id, m := range res.MessageList {
+			if u, err := time.Parse(time.RFC3339, m.DateUpdated); err == nil && u.After(t.since) {
+				t.since = u
+			}
+			prev, seen := t.status[id]
+			if seen && prev.Status == m.Status && prev.StatusCode == m.StatusCode {
+				continue
+			}
+			t.status[id] = m
+			changes = append(changes, tailChange{
+				MessageID:      id,
+				To:             m.To,
+				Status:         m.Status,
+				StatusCode:     m.StatusCode,
+				PrevStatus:     prev.Status,
+				PrevStatusCode: prev.StatusCode,
+				DateUpdated:    m.DateUpdated,
+			})
+		}
+		if res.NextKey == "" || res.NextKey == q.StartKey {
+			break
+		}
+		q.StartKey = res.NextKey
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].DateUpdated != changes[j].DateUpdated {
+			return changes[i].DateUpdated < changes[j].DateUpdated
+		}
+		return changes[i].MessageID < changes[j].MessageID
+	})
+	return changes, nil
+}
+
+// count tallies the seen messages like messages.GroupCount. Status code 4000
+// is a delivered message; 2000 and 3000 are still on their way.
+func (t *tailer) count() messages.GroupCount {
+	var c messages.GroupCount
+	for _, m := range t.status {
+		c.Total++
+		switch m.StatusCode {
+		case "", "2000", "3000":
+			c.SentPending++
+		case "4000":
+			c.SentSuccess++
+		default:
+			c.SentFailed++
+		}
+		if m.Replacement != nil && *m.Replacement {
+			c.SentReplacement++
+		}
+	}
+	c.SentTotal = c.SentSuccess + c.SentFailed
+	return c
+}
+
+func (a *app) printTail(e tailEvent) error {
+	if a.json {
+		return json.NewEncoder(a.stdout).Encode(e)
+	}
+	ts := e.Time.Format("15:04:05")
+	for _, ch := range e.Changes {
+		from := "new"
+		if ch.PrevStatus != "" || ch.PrevStatusCode != "" {
+			from = strings.TrimSpace(ch.PrevStatus + " " + ch.PrevStatusCode)
+		}
+		if _, err := fmt.Fprintf(a.stdout, "%s  %s  %s  %s -> %s %s\n", ts, ch.MessageID, ch.To, from, ch.Status, ch.StatusCode); err != nil {
+			return err
+		}
+	}
+	c := e.Count
+	_, err := fmt.Fprintf(a.stdout, "%s  group %s  total %d  pending %d  success %d  failed %d  replacement %d\n",
+		ts, e.GroupStatus, c.Total, c.SentPending, c.SentSuccess, c.SentFailed, c.SentReplacement)
+	return err
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tailServer serves a group whose messages advance one step per list call.
+func tailServer(t *testing.T, final bool) (http.HandlerFunc, *[]url.Values) {
+	lists := []string{
+		`{"messageList":{"M1":{"to":"01011112222","status":"PENDING","statusCode":"2000","dateUpdated":"2026-01-01T00:00:00Z"},
+			"M2":{"to":"01033334444","status":"PENDING","statusCode":"2000","dateUpdated":"2026-01-01T00:00:00Z"}}}`,
+		`{"messageList":{"M1":{"to":"01011112222","status":"COMPLETE","statusCode":"4000","dateUpdated":"2026-01-01T00:00:05Z"}}}`,
+		`{"messageList":{"M1":{"to":"01011112222","status":"COMPLETE","statusCode":"4000","dateUpdated":"2026-01-01T00:00:05Z"},
+			"M2":{"to":"01033334444","status":"COMPLETE","statusCode":"3059","replacement":true,"dateUpdated":"2026-01-01T00:00:09Z"}}}`,
+	}
+	var mu sync.Mutex
+	var queries []url.Values
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/messages/v4/groups/G1":
+			status := "SENDING"
+			if final && len(queries) == len(lists)-1 {
+				status = "COMPLETE"
+			}
+			w.Write([]byte(`{"groupInfo":{"status":"` + status + `"}}`))
+		case "/messages/v4/list":
+			queries = append(queries, r.URL.Query())
+			w.Write([]byte(lists[min(len(queries), len(lists))-1]))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}, &queries
+}
+
+func TestMessagesTail(t *testing.T) {
+	h, queries := tailServer(t, true)
+	out, errOut, code := runCLI(t, h, "messages", "tail", "--group", "G1", "-interval", "1ms")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	want := []string{
+		"M1  01011112222  new -> PENDING 2000",
+		"M2  01033334444  new -> PENDING 2000",
+		"group SENDING  total 2  pending 2  success 0  failed 0  replacement 0",
+		"M1  01011112222  PENDING 2000 -> COMPLETE 4000",
+		"group SENDING  total 2  pending 1  success 1  failed 0  replacement 0",
+		"M2  01033334444  PENDING 2000 -> COMPLETE 3059",
+		"group COMPLETE  total 2  pending 0  success 1  failed 1  replacement 1",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("output:\n%s", out)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], w)
+		}
+	}
+
+	qs := *queries
+	if qs[0].Get("groupId") != "G1" || qs[0].Get("startDate") != "" {
+		t.Fatalf("first query: %v", qs[0])
+	}
+	if qs[1].Get("dateType") != "UPDATED" || !strings.HasPrefix(qs[1].Get("startDate"), "2025-12-31T23:59:58") {
+		t.Fatalf("incremental query: %v", qs[1])
+	}
+}
+
+func TestMessagesTailJSON(t *testing.T) {
+	h, _ := tailServer(t, true)
+	out, errOut, code := runCLI(t, h, "-json", "messages", "tail", "-group", "G1", "-interval", "1ms")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	dec := json.NewDecoder(strings.NewReader(out))
+	var last tailEvent
+	n := 0
+	for dec.More() {
+		if err := dec.Decode(&last); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 3 || last.GroupStatus != "COMPLETE" || last.Count.SentFailed != 1 || last.Changes[0].PrevStatus != "PENDING" {
+		t.Fatalf("%d events, last %+v", n, last)
+	}
+}
+
+func TestMessagesTailStopsOnCancel(t *testing.T) {
+	h, _ := tailServer(t, false)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	var out, errOut bytes.Buffer
+	a := &app{stdout: &out, stderr: &errOut, httpClient: &http.Client{Transport: redirect{u}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if code := a.run(ctx, []string{"messages", "tail", "-group", "G1", "-interval", "5ms"}); code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "failed 1") {
+		t.Fatalf("output:\n%s", out.String())
+	}
+}
+
+func TestMessagesTailRequiresGroup(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) { t.Errorf("unexpected request %s", r.URL) }
+	if _, _, code := runCLI(t, h, "messages", "tail"); code != 2 {
+		t.Fatalf("exit %d, want 2", code)
+	}
+}
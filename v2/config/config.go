@@ -0,0 +1,239 @@
+// Package config loads SOLAPI settings from named profiles in a config file,
+// with environment variables taking precedence.
+//
+// The file lives at $XDG_CONFIG_HOME/solapi/config, or
+// ~/.config/solapi/config when XDG_CONFIG_HOME is unset, unless SOLAPI_CONFIG
+// names another path. Each profile is a section of key = value
+// lines:
+//
+//	[default]
+//	api_key = NCS...
+//	api_secret = ...
+//
+//	[staging]
+//	api_key = NCS...
+//	api_secret = ...
+//	base_url = https://staging.example.com
+//	app_id = ...
+//	from = 0212345678
+//
+// Lines starting with # or ; are comments.
+//
+// SOLAPI_BASE_URL, SOLAPI_APP_ID and SOLAPI_FROM override the profile's keys.
+// SOLAPI_API_KEY and SOLAPI_API_SECRET are applied only as a pair, and only
+// to the implicit default profile or to a profile without credentials of its
+// own, so a profile chosen with -profile or SOLAPI_PROFILE is never sent with
+// another account's key.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+// DefaultProfile is used when neither the caller nor SOLAPI_PROFILE names one.
+const DefaultProfile = "default"
+
+// Environment variables read by Load. See the package documentation for how
+// they take precedence over the profile.
+const (
+	EnvConfigFile = "SOLAPI_CONFIG"
+	EnvProfile    = "SOLAPI_PROFILE"
+	EnvAPIKey     = auth.EnvAPIKey
+	EnvAPISecret  = auth.EnvAPISecret
+	EnvBaseURL    = "SOLAPI_BASE_URL"
+	EnvAppID      = "SOLAPI_APP_ID"
+	EnvFrom       = "SOLAPI_FROM"
+)
+
+var (
+	// ErrProfileNotFound is returned when a named profile is not in the file.
+	ErrProfileNotFound = errors.New("config: profile not found")
+	// ErrNoCredentials is returned when neither the profile nor the
+	// environment provides an API key and secret.
+	ErrNoCredentials = errors.New("config: no API key and secret configured")
+)
+
+// Profile is one named set of settings.
+type Profile struct {
+	Name      string
+	APIKey    string
+	APISecret string
+	// BaseURL overrides the API endpoint; empty uses the default.
+	BaseURL string
+	// AppId is applied to sends and groups that do not set one.
+	AppId string
+	// From is the sender number applied to messages without one.
+	From string
+}
+
+// SyntaxError reports a malformed line in a config file.
+type SyntaxError struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("config: %s:%d: %s", e.Path, e.Line, e.Msg)
+}
+
+// Path returns the config file path: SOLAPI_CONFIG if set, otherwise
+// solapi/config under $XDG_CONFIG_HOME or ~/.config.
+func Path() (string, error) {
+	if p := os.Getenv(EnvConfigFile); p != "" {
+		return p, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "solapi", "config"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("config: %w", err)
+	}
+	return filepath.Join(home, ".config", "solapi", "config"), nil
+}
+
+// Load resolves profile from the config file and the environment. An empty
+// profile means SOLAPI_PROFILE, or DefaultProfile when that is unset.
+//
+// A missing file is not an error when it is the default path and the
+// environment supplies the credentials. The default profile may likewise be
+// absent; any other profile must exist in the file.
+func Load(profile string) (Profile, error) {
+	path, err := Path()
+	if err != nil {
+		return Profile{}, err
+	}
+	profiles, err := ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && os.Getenv(EnvConfigFile) == "" {
+		err = nil
+	}
+	if err != nil {
+		return Profile{}, err
+	}
+	return resolve(profiles, profile, path)
+}
+
+// ReadFile parses the profiles in the config file at path.
+func ReadFile(path string) (map[string]Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	return parse(f, path)
+}
+
+// Parse parses profiles in the config file format from r.
+func Parse(r io.Reader) (map[string]Profile, error) {
+	return parse(r, "config")
+}
+
+func parse(r io.Reader, path string) (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+	var cur *Profile
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, &SyntaxError{path, n, "unterminated section header"}
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, &SyntaxError{path, n, "empty profile name"}
+			}
+			if cur != nil {
+				profiles[cur.Name] = *cur
+			}
+			p := profiles[name]
+			p.Name = name
+			cur = &p
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, &SyntaxError{path, n, "expected key = value"}
+		}
+		if cur == nil {
+			return nil, &SyntaxError{path, n, "key outside of a [profile] section"}
+		}
+		key, value = strings.TrimSpace(key), unquote(strings.TrimSpace(value))
+		switch key {
+		case "api_key":
+			cur.APIKey = value
+		case "api_secret":
+			cur.APISecret = value
+		case "base_url":
+			cur.BaseURL = value
+		case "app_id":
+			cur.AppId = value
+		case "from":
+			cur.From = value
+		default:
+			return nil, &SyntaxError{path, n, fmt.Sprintf("unknown key %q", key)}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	if cur != nil {
+		profiles[cur.Name] = *cur
+	}
+	return profiles, nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// resolve picks the profile and applies the environment overrides.
+func resolve(profiles map[string]Profile, name, path string) (Profile, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	explicit := name != ""
+	if name == "" {
+		name = DefaultProfile
+	}
+	p, ok := profiles[name]
+	if !ok && name != DefaultProfile {
+		return Profile{}, fmt.Errorf("%w: %q in %s", ErrProfileNotFound, name, path)
+	}
+	p.Name = name
+	for env, field := range map[string]*string{
+		EnvBaseURL: &p.BaseURL,
+		EnvAppID:   &p.AppId,
+		EnvFrom:    &p.From,
+	} {
+		if v := os.Getenv(env); v != "" {
+			*field = v
+		}
+	}
+	// the credentials are only replaced together, and never those of a
+	// profile the caller asked for by name
+	key, secret := os.Getenv(EnvAPIKey), os.Getenv(EnvAPISecret)
+	hasOwn := p.APIKey != "" && p.APISecret != ""
+	if key != "" && secret != "" && (!explicit || !hasOwn) {
+		p.APIKey, p.APISecret = key, secret
+	}
+	if p.APIKey == "" || p.APISecret == "" {
+		return p, fmt.Errorf("%w for profile %q: set %s and %s or add them to %s", ErrNoCredentials, name, EnvAPIKey, EnvAPISecret, path)
+	}
+	return p, nil
+}
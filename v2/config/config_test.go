@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sample = `
+# comment
+[default]
+api_key = KEY
+api_secret = "SECRET"
+
+[staging]
+api_key = SKEY
+api_secret = SSECRET
+base_url = https://staging.example.com
+app_id = APP
+from = 0212345678
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	for _, env := range []string{EnvProfile, EnvAPIKey, EnvAPISecret, EnvBaseURL, EnvAppID, EnvFrom} {
+		t.Setenv(env, "")
+	}
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvConfigFile, path)
+	return path
+}
+
+func TestLoad_Profiles(t *testing.T) {
+	writeConfig(t, sample)
+	p, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "default" || p.APIKey != "KEY" || p.APISecret != "SECRET" || p.BaseURL != "" {
+		t.Fatalf("default profile: %+v", p)
+	}
+	p, err = Load("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Profile{Name: "staging", APIKey: "SKEY", APISecret: "SSECRET", BaseURL: "https://staging.example.com", AppId: "APP", From: "0212345678"}
+	if p != want {
+		t.Fatalf("got %+v, want %+v", p, want)
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	writeConfig(t, sample)
+	t.Setenv(EnvAPIKey, "ENVKEY")
+	t.Setenv(EnvAPISecret, "ENVSECRET")
+	t.Setenv(EnvFrom, "01000000000")
+	p, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "default" || p.APIKey != "ENVKEY" || p.APISecret != "ENVSECRET" || p.From != "01000000000" {
+		t.Fatalf("default profile: %+v", p)
+	}
+
+	// a profile named by SOLAPI_PROFILE or the caller keeps its credentials
+	t.Setenv(EnvProfile, "staging")
+	p, err = Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "staging" || p.APIKey != "SKEY" || p.APISecret != "SSECRET" || p.From != "01000000000" {
+		t.Fatalf("staging profile: %+v", p)
+	}
+	t.Setenv(EnvProfile, "")
+	if p, _ = Load(DefaultProfile); p.APIKey != "KEY" || p.APISecret != "SECRET" {
+		t.Fatalf("explicit default profile: %+v", p)
+	}
+}
+
+func TestLoad_EnvCredentialsPair(t *testing.T) {
+	writeConfig(t, sample+"\n[empty]\nfrom = 0212345678\n")
+	t.Setenv(EnvAPISecret, "ENVSECRET")
+	p, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.APIKey != "KEY" || p.APISecret != "SECRET" {
+		t.Fatalf("lone secret applied: %+v", p)
+	}
+	if _, err := Load("empty"); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("lone secret: %v", err)
+	}
+	t.Setenv(EnvAPIKey, "ENVKEY")
+	if p, err = Load("empty"); err != nil || p.APIKey != "ENVKEY" || p.APISecret != "ENVSECRET" {
+		t.Fatalf("profile without credentials: %+v %v", p, err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv(EnvConfigFile, "")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	t.Setenv("HOME", "/home/u")
+	if p, err := Path(); err != nil || p != filepath.Join("/xdg", "solapi", "config") {
+		t.Fatalf("XDG_CONFIG_HOME: %q %v", p, err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if p, err := Path(); err != nil || p != filepath.Join("/home/u", ".config", "solapi", "config") {
+		t.Fatalf("HOME: %q %v", p, err)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	path := writeConfig(t, sample)
+	if _, err := Load("prod"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("missing profile: %v", err)
+	}
+
+	writeConfig(t, "[default]\napi_key = KEY\n")
+	if _, err := Load(""); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("missing secret: %v", err)
+	}
+
+	os.Remove(path)
+	t.Setenv(EnvConfigFile, path)
+	if _, err := Load(""); err == nil {
+		t.Fatal("expected error for missing explicit config file")
+	}
+}
+
+func TestLoad_MissingDefaultFileUsesEnv(t *testing.T) {
+	writeConfig(t, "")
+	t.Setenv(EnvConfigFile, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvAPIKey, "K")
+	t.Setenv(EnvAPISecret, "S")
+	p, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.APIKey != "K" || p.APISecret != "S" {
+		t.Fatalf("got %+v", p)
+	}
+}
+
+func TestParse_SyntaxErrors(t *testing.T) {
+	for _, in := range []string{
+		"api_key = K",
+		"[default\napi_key = K",
+		"[]",
+		"[default]\napi_key",
+		"[default]\ncolor = blue",
+	} {
+		_, err := Parse(strings.NewReader(in))
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("%q: got %v, want *SyntaxError", in, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package credentials exposes the credential providers used to sign SOLAPI
+// requests. Pass a Provider to client.NewClientWithProvider to rotate API
+// secrets without rebuilding clients.
+package credentials
+
+import (
+	"context"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+// Value is an API key and secret pair.
+type Value = auth.AuthenticationParameter
+
+// Provider supplies credentials per request. Implementations must be safe for
+// concurrent use.
+type Provider = auth.CredentialsProvider
+
+// Environment variables read by FromEnv.
+const (
+	EnvAPIKey    = auth.EnvAPIKey
+	EnvAPISecret = auth.EnvAPISecret
+)
+
+// Static returns a provider that always yields the same pair.
+func Static(apiKey, apiSecret string) Provider {
+	return Value{ApiKey: apiKey, ApiSecret: apiSecret}
+}
+
+// FromEnv reads SOLAPI_API_KEY and SOLAPI_API_SECRET on every request.
+func FromEnv() Provider {
+	return auth.EnvProvider{}
+}
+
+// FromEnvVars is like FromEnv with custom variable names.
+func FromEnvVars(keyVar, secretVar string) Provider {
+	return auth.EnvProvider{KeyVar: keyVar, SecretVar: secretVar}
+}
+
+// FromFile reads {"apiKey": "...", "apiSecret": "..."} from path and reloads
+// it whenever the file changes.
+func FromFile(path string) Provider {
+	return auth.NewFileProvider(path)
+}
+
+// Func adapts a lookup function, e.g. a secret manager client, to a Provider.
+func Func(fn func(ctx context.Context) (Value, error)) Provider {
+	return auth.ProviderFunc(fn)
+}
+
+// Cached caches the credentials of p for ttl. The cache is dropped early when
+// the API rejects the credentials.
+func Cached(p Provider, ttl time.Duration) Provider {
+	return auth.NewCachingProvider(p, ttl)
+}
+
+// Rotating holds credentials that can be swapped atomically with Rotate.
+type Rotating = auth.RotatingProvider
+
+// NewRotating returns a Rotating provider holding the given pair.
+func NewRotating(apiKey, apiSecret string) *Rotating {
+	return auth.NewRotatingProvider(Value{ApiKey: apiKey, ApiSecret: apiSecret})
+}
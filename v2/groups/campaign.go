@@ -0,0 +1,373 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/messages"
+	"github.com/solapi/solapi-go/v2/solapierr"
+)
+
+const (
+	// DefaultCampaignChunkSize is the maximum number of messages a single
+	// AddMessages call accepts.
+	DefaultCampaignChunkSize = 10000
+	// DefaultCampaignConcurrency bounds in-flight AddMessages calls.
+	DefaultCampaignConcurrency = 4
+)
+
+// ErrEmptyCampaign is returned when a campaign is finalized without messages.
+var ErrEmptyCampaign = errors.New("groups: campaign has no messages")
+
+// CampaignOptions configures a Campaign.
+type CampaignOptions struct {
+	// Group is passed to Create when the group is lazily created.
+	Group CreateGroupOptions
+	// ChunkSize is the number of messages per AddMessages call.
+	// Zero or values above DefaultCampaignChunkSize fall back to the default.
+	ChunkSize int
+	// Concurrency is the maximum number of concurrent AddMessages calls.
+	Concurrency int
+	// AllowDuplicates is forwarded to every AddMessages call.
+	AllowDuplicates *bool
+	// Checkpoint, when set, records the group ID and completed chunks so an
+	// interrupted campaign can be continued with ResumeCampaign.
+	Checkpoint Checkpoint
+}
+
+// CampaignResult summarizes a finalized campaign.
+type CampaignResult struct {
+	GroupID string
+	// Added is the number of messages streamed into the campaign, including
+	// chunks skipped because they had already landed before a resume.
+	Added int
+	// FailedMessageList aggregates registration failures across all chunks.
+	FailedMessageList []messages.FailedMessage
+	// Filtered aggregates recipients removed or rewritten by recipient
+	// filters. Indexes are positions in the campaign's message stream.
+	Filtered []messages.FilteredRecipient
+	// Response is the response of the final Send or Reserve call.
+	Response messages.DetailGroupMessageResponse
+}
+
+// CampaignError is returned when a campaign fails fatally. The group is removed
+// before the error is returned; RollbackErr is set when that removal failed.
+//
+// When the campaign has a Checkpoint and the failure is transient (a network
+// error, throttling or a server error), the group and checkpoint are kept
+// instead and Resumable is set, so the campaign can be continued with
+// ResumeCampaign. Call Abort on it to remove the group after all.
+type CampaignError struct {
+	GroupID     string
+	Op          string
+	Err         error
+	RollbackErr error
+	Resumable   bool
+}
+
+func (e *CampaignError) Error() string {
+	msg := fmt.Sprintf("groups: campaign %s failed during %s: %v", e.GroupID, e.Op, e.Err)
+	if e.RollbackErr != nil {
+		msg += fmt.Sprintf(" (rollback failed: %v)", e.RollbackErr)
+	}
+	return msg
+}
+
+func (e *CampaignError) Unwrap() error { return e.Err }
+
+// Campaign builds a group by streaming messages into it. Messages are buffered
+// and flushed to AddMessages in chunks with bounded concurrency; the group is
+// created on the first Add and finalized with Send or Reserve.
+//
+// The context passed to Add is used by the chunk uploads it starts, so it must
+// stay valid until the campaign is finalized.
+type Campaign struct {
+	svc *Service
+	opt CampaignOptions
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	// mu guards the buffer and group creation.
+	mu      sync.Mutex
+	groupID string
+	buf     []messages.Message
+	offset  int
+	done    bool
+
+	// resMu guards results written by chunk goroutines.
+	resMu     sync.Mutex
+	failed    []messages.FailedMessage
+	filtered  []messages.FilteredRecipient
+	completed map[int]bool
+	err       error
+}
+
+// NewCampaign returns a Campaign backed by the service.
+func (s *Service) NewCampaign(opt CampaignOptions) *Campaign {
+	if opt.ChunkSize <= 0 || opt.ChunkSize > DefaultCampaignChunkSize {
+		opt.ChunkSize = DefaultCampaignChunkSize
+	}
+	if opt.Concurrency <= 0 {
+		opt.Concurrency = DefaultCampaignConcurrency
+	}
+	return &Campaign{
+		svc:       s,
+		opt:       opt,
+		sem:       make(chan struct{}, opt.Concurrency),
+		completed: map[int]bool{},
+	}
+}
+
+// GroupID returns the ID of the underlying group, or "" before the first Add.
+func (c *Campaign) GroupID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.groupID
+}
+
+// Add buffers messages and uploads every full chunk. It returns the first
+// fatal error seen so far, after which the campaign should be aborted.
+func (c *Campaign) Add(ctx context.Context, msgs ...messages.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done {
+		return errors.New("groups: campaign already finalized")
+	}
+	if err := c.firstErr(); err != nil {
+		return err
+	}
+	if err := c.ensureGroup(ctx); err != nil {
+		return err
+	}
+	c.buf = append(c.buf, msgs...)
+	for len(c.buf) >= c.opt.ChunkSize {
+		chunk := c.buf[:c.opt.ChunkSize:c.opt.ChunkSize]
+		c.buf = c.buf[c.opt.ChunkSize:]
+		if err := c.dispatch(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return c.firstErr()
+}
+
+// AddFrom drains ch into the campaign until it is closed or ctx is done.
+func (c *Campaign) AddFrom(ctx context.Context, ch <-chan messages.Message) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case m, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := c.Add(ctx, m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Send flushes pending messages, waits for all chunks and sends the group.
+// On a fatal error a *CampaignError is returned and the group is removed,
+// unless the error leaves the campaign resumable.
+func (c *Campaign) Send(ctx context.Context) (CampaignResult, error) {
+	return c.finalize(ctx, "send", func(groupID string) (messages.DetailGroupMessageResponse, error) {
+		return c.svc.Send(ctx, groupID)
+	})
+}
+
+// Reserve is like Send but schedules the group for scheduledDate.
+func (c *Campaign) Reserve(ctx context.Context, scheduledDate string) (CampaignResult, error) {
+	return c.finalize(ctx, "reserve", func(groupID string) (messages.DetailGroupMessageResponse, error) {
+		return c.svc.Reserve(ctx, groupID, scheduledDate)
+	})
+}
+
+// ReserveAt is like Reserve but takes a typed time; see Service.ReserveAt.
+func (c *Campaign) ReserveAt(ctx context.Context, t time.Time) (CampaignResult, error) {
+	if err := messages.ValidateSchedule(t); err != nil {
+		return CampaignResult{}, err
+	}
+	return c.Reserve(ctx, messages.FormatDate(t))
+}
+
+// Abort waits for in-flight chunks and removes the group.
+func (c *Campaign) Abort(ctx context.Context) error {
+	c.mu.Lock()
+	c.done = true
+	c.buf = nil
+	groupID := c.groupID
+	c.mu.Unlock()
+	c.wg.Wait()
+	if groupID == "" {
+		return nil
+	}
+	ctx = context.WithoutCancel(ctx)
+	if _, err := c.svc.RemoveGroup(ctx, groupID); err != nil {
+		return err
+	}
+	if c.opt.Checkpoint != nil {
+		return c.opt.Checkpoint.Clear(ctx)
+	}
+	return nil
+}
+
+func (c *Campaign) finalize(ctx context.Context, op string, fn func(groupID string) (messages.DetailGroupMessageResponse, error)) (CampaignResult, error) {
+	c.mu.Lock()
+	if c.done {
+		c.mu.Unlock()
+		return CampaignResult{}, errors.New("groups: campaign already finalized")
+	}
+	c.done = true
+	if len(c.buf) > 0 && c.firstErr() == nil {
+		chunk := c.buf
+		c.buf = nil
+		_ = c.dispatch(ctx, chunk)
+	}
+	groupID, added := c.groupID, c.offset
+	c.mu.Unlock()
+
+	c.wg.Wait()
+	if groupID == "" {
+		return CampaignResult{}, ErrEmptyCampaign
+	}
+	res := CampaignResult{GroupID: groupID, Added: added, FailedMessageList: c.failedList(), Filtered: c.filteredList()}
+	if err := c.firstErr(); err != nil {
+		return res, c.rollback(ctx, groupID, "add messages", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return res, c.rollback(ctx, groupID, op, err)
+	}
+	out, err := fn(groupID)
+	if err != nil {
+		return res, c.rollback(ctx, groupID, op, err)
+	}
+	res.Response = out
+	if c.opt.Checkpoint != nil {
+		if err := c.opt.Checkpoint.Clear(ctx); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// ensureGroup creates the group on first use. Callers must hold c.mu.
+func (c *Campaign) ensureGroup(ctx context.Context) error {
+	if c.groupID != "" {
+		return nil
+	}
+	res, err := c.svc.Create(ctx, c.opt.Group)
+	if err != nil {
+		return err
+	}
+	c.groupID = res.GroupID
+	return c.saveCheckpoint(ctx)
+}
+
+// dispatch uploads one chunk asynchronously once a concurrency slot is free.
+// Chunks already recorded as completed are skipped. Callers must hold c.mu.
+func (c *Campaign) dispatch(ctx context.Context, chunk []messages.Message) error {
+	offset := c.offset
+	c.offset += len(chunk)
+	if c.isCompleted(offset) {
+		return nil
+	}
+	if c.opt.Checkpoint != nil {
+		chunk = tagChunk(chunk, offset)
+	}
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		c.setErr(ctx.Err())
+		return ctx.Err()
+	}
+	groupID := c.groupID
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer func() { <-c.sem }()
+		res, err := c.svc.AddMessages(ctx, groupID, AddGroupMessagesRequest{
+			Messages:        chunk,
+			AllowDuplicates: c.opt.AllowDuplicates,
+		})
+		// a chunk left empty by the filters is reported, not fatal
+		if err != nil && !errors.Is(err, messages.ErrNoRecipients) {
+			c.setErr(err)
+			return
+		}
+		c.resMu.Lock()
+		c.failed = append(c.failed, res.FailedMessageList...)
+		for _, f := range res.Filtered {
+			f.Index += offset
+			c.filtered = append(c.filtered, f)
+		}
+		c.completed[offset] = true
+		c.resMu.Unlock()
+		if err := c.saveCheckpoint(ctx); err != nil {
+			c.setErr(err)
+		}
+	}()
+	return nil
+}
+
+func (c *Campaign) rollback(ctx context.Context, groupID, op string, err error) error {
+	if c.opt.Checkpoint != nil && transient(err) {
+		return &CampaignError{GroupID: groupID, Op: op, Err: err, Resumable: true}
+	}
+	ctx = context.WithoutCancel(ctx)
+	_, rbErr := c.svc.RemoveGroup(ctx, groupID)
+	if rbErr == nil && c.opt.Checkpoint != nil {
+		rbErr = c.opt.Checkpoint.Clear(ctx)
+	}
+	return &CampaignError{GroupID: groupID, Op: op, Err: err, RollbackErr: rbErr}
+}
+
+// transient reports whether err is expected to clear on its own, so that a
+// resumed campaign may succeed.
+func transient(err error) bool {
+	var uerr *url.Error
+	var temp interface{ Temporary() bool }
+	switch {
+	case errors.As(err, &uerr), errors.Is(err, solapierr.ErrRateLimited), errors.Is(err, solapierr.ErrCircuitOpen):
+		return true
+	case errors.As(err, &temp):
+		return temp.Temporary()
+	}
+	return false
+}
+
+func (c *Campaign) isCompleted(offset int) bool {
+	c.resMu.Lock()
+	defer c.resMu.Unlock()
+	return c.completed[offset]
+}
+
+func (c *Campaign) setErr(err error) {
+	c.resMu.Lock()
+	defer c.resMu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+func (c *Campaign) firstErr() error {
+	c.resMu.Lock()
+	defer c.resMu.Unlock()
+	return c.err
+}
+
+func (c *Campaign) failedList() []messages.FailedMessage {
+	c.resMu.Lock()
+	defer c.resMu.Unlock()
+	return append([]messages.FailedMessage(nil), c.failed...)
+}
+
+func (c *Campaign) filteredList() []messages.FilteredRecipient {
+	c.resMu.Lock()
+	defer c.resMu.Unlock()
+	return append([]messages.FilteredRecipient(nil), c.filtered...)
+}
@@ -0,0 +1,119 @@
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+type fakeGroupServer struct {
+	mu       sync.Mutex
+	chunks   []int
+	removed  bool
+	sent     bool
+	failAdds bool
+}
+
+func (f *fakeGroupServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/messages/v4/groups":
+			_ = json.NewEncoder(w).Encode(map[string]any{"groupId": "g1"})
+		case r.Method == http.MethodPut && r.URL.Path == "/messages/v4/groups/g1/messages":
+			if f.failAdds {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var body AddGroupMessagesRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode: %v", err)
+			}
+			f.mu.Lock()
+			f.chunks = append(f.chunks, len(body.Messages))
+			f.mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"groupInfo":         map[string]any{},
+				"failedMessageList": []any{map[string]any{"to": body.Messages[0].To, "statusCode": "1062"}},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/messages/v4/groups/g1/send":
+			f.mu.Lock()
+			f.sent = true
+			f.mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{"groupId": "g1"}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/messages/v4/groups/g1":
+			f.mu.Lock()
+			f.removed = true
+			f.mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{"groupId": "g1"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestCampaign_ChunksAndSends(t *testing.T) {
+	f := &fakeGroupServer{}
+	ts := httptest.NewServer(f.handler(t))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	c := svc.NewCampaign(CampaignOptions{ChunkSize: 2, Concurrency: 2})
+	for i := 0; i < 5; i++ {
+		if err := c.Add(context.Background(), messages.Message{To: "010"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	res, err := c.Send(context.Background())
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if res.GroupID != "g1" || res.Added != 5 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(f.chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %v", f.chunks)
+	}
+	if len(res.FailedMessageList) != 3 {
+		t.Fatalf("expected failures from every chunk, got %d", len(res.FailedMessageList))
+	}
+	if !f.sent || f.removed {
+		t.Fatalf("sent=%v removed=%v", f.sent, f.removed)
+	}
+}
+
+func TestCampaign_RollsBackOnAddFailure(t *testing.T) {
+	f := &fakeGroupServer{failAdds: true}
+	ts := httptest.NewServer(f.handler(t))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	c := svc.NewCampaign(CampaignOptions{ChunkSize: 1})
+	_ = c.Add(context.Background(), messages.Message{To: "010"})
+	_, err := c.Send(context.Background())
+	var ce *CampaignError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected CampaignError, got %v", err)
+	}
+	if ce.RollbackErr != nil {
+		t.Fatalf("unexpected rollback error: %v", ce.RollbackErr)
+	}
+	if f.sent || !f.removed {
+		t.Fatalf("sent=%v removed=%v", f.sent, f.removed)
+	}
+}
+
+func TestCampaign_EmptyReturnsError(t *testing.T) {
+	svc := NewService("http://invalid", auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	_, err := svc.NewCampaign(CampaignOptions{}).Send(context.Background())
+	if !errors.Is(err, ErrEmptyCampaign) {
+		t.Fatalf("expected ErrEmptyCampaign, got %v", err)
+	}
+}
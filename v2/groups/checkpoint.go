@@ -0,0 +1,216 @@
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// CampaignChunkField is the CustomFields key a checkpointed campaign sets on
+// every message. Its value is the stream offset of the message's chunk and is
+// used to reconcile chunks that landed before a crash but were not recorded.
+const CampaignChunkField = "campaignChunk"
+
+// CheckpointState is the persisted progress of a campaign.
+type CheckpointState struct {
+	GroupID   string `json:"groupId"`
+	ChunkSize int    `json:"chunkSize"`
+	// Completed holds the stream offsets of chunks known to be in the group.
+	Completed []int `json:"completed"`
+}
+
+// Checkpoint persists campaign progress. Load returns a zero state when
+// nothing was saved yet.
+type Checkpoint interface {
+	Load(ctx context.Context) (CheckpointState, error)
+	Save(ctx context.Context, st CheckpointState) error
+	Clear(ctx context.Context) error
+}
+
+// MemoryCheckpoint keeps the state in memory. It is mostly useful in tests and
+// for resuming within the same process.
+type MemoryCheckpoint struct {
+	mu sync.Mutex
+	st CheckpointState
+}
+
+func (m *MemoryCheckpoint) Load(ctx context.Context) (CheckpointState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.st
+	st.Completed = append([]int(nil), m.st.Completed...)
+	return st, nil
+}
+
+func (m *MemoryCheckpoint) Save(ctx context.Context, st CheckpointState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st.Completed = append([]int(nil), st.Completed...)
+	m.st = st
+	return nil
+}
+
+func (m *MemoryCheckpoint) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.st = CheckpointState{}
+	return nil
+}
+
+// FileCheckpoint stores the state as JSON at Path. Writes go through a
+// temporary file and a rename so a crash never leaves a torn file behind.
+type FileCheckpoint struct {
+	Path string
+}
+
+// NewFileCheckpoint returns a FileCheckpoint writing to path.
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{Path: path}
+}
+
+func (f *FileCheckpoint) Load(ctx context.Context) (CheckpointState, error) {
+	var st CheckpointState
+	b, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(b, &st)
+	return st, err
+}
+
+func (f *FileCheckpoint) Save(ctx context.Context, st CheckpointState) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+func (f *FileCheckpoint) Clear(ctx context.Context) error {
+	err := os.Remove(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ResumeCampaign returns a campaign that continues from opt.Checkpoint.
+// When a previous run left a group behind, its messages are listed and chunks
+// carrying a CampaignChunkField marker are treated as completed even if the
+// checkpoint missed them. The caller must then re-add the same messages in the
+// same order; chunks that already landed are skipped instead of re-uploaded.
+func (s *Service) ResumeCampaign(ctx context.Context, opt CampaignOptions) (*Campaign, error) {
+	if opt.Checkpoint == nil {
+		return nil, errors.New("groups: ResumeCampaign requires a Checkpoint")
+	}
+	st, err := opt.Checkpoint.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if st.ChunkSize > 0 {
+		opt.ChunkSize = st.ChunkSize
+	}
+	c := s.NewCampaign(opt)
+	if st.GroupID == "" {
+		return c, nil
+	}
+	c.groupID = st.GroupID
+	for _, off := range st.Completed {
+		c.completed[off] = true
+	}
+	landed, err := s.listChunkMarkers(ctx, st.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	for off := range landed {
+		c.completed[off] = true
+	}
+	if err := c.saveCheckpoint(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// listChunkMarkers collects the chunk offsets found on messages in the group.
+func (s *Service) listChunkMarkers(ctx context.Context, groupID string) (map[int]bool, error) {
+	out := map[int]bool{}
+	q := ListMessagesQuery{Limit: 500}
+	for {
+		res, err := s.ListMessages(ctx, groupID, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range res.MessageList {
+			v, ok := m.CustomFields[CampaignChunkField]
+			if !ok {
+				continue
+			}
+			if off, err := strconv.Atoi(v); err == nil {
+				out[off] = true
+			}
+		}
+		if res.NextKey == "" || res.NextKey == q.StartKey {
+			return out, nil
+		}
+		q.StartKey = res.NextKey
+	}
+}
+
+// saveCheckpoint persists the current progress if a Checkpoint is configured.
+func (c *Campaign) saveCheckpoint(ctx context.Context) error {
+	if c.opt.Checkpoint == nil {
+		return nil
+	}
+	c.resMu.Lock()
+	defer c.resMu.Unlock()
+	st := CheckpointState{GroupID: c.groupID, ChunkSize: c.opt.ChunkSize}
+	for off := range c.completed {
+		st.Completed = append(st.Completed, off)
+	}
+	sort.Ints(st.Completed)
+	return c.opt.Checkpoint.Save(ctx, st)
+}
+
+// tagChunk copies chunk and marks every message with the chunk offset.
+func tagChunk(chunk []messages.Message, offset int) []messages.Message {
+	out := make([]messages.Message, len(chunk))
+	v := strconv.Itoa(offset)
+	for i, m := range chunk {
+		fields := make(map[string]string, len(m.CustomFields)+1)
+		for k, val := range m.CustomFields {
+			fields[k] = val
+		}
+		fields[CampaignChunkField] = v
+		m.CustomFields = fields
+		out[i] = m
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestResumeCampaign_SkipsLandedChunks(t *testing.T) {
+	var mu sync.Mutex
+	var uploaded []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/messages/v4/groups/g1/messages":
+			// chunk at offset 2 landed but was never recorded in the checkpoint
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"messageList": map[string]any{
+					"m1": map[string]any{"to": "012", "customFields": map[string]any{CampaignChunkField: "2"}},
+				},
+			})
+		case r.Method == http.MethodPut && r.URL.Path == "/messages/v4/groups/g1/messages":
+			var body AddGroupMessagesRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			for _, m := range body.Messages {
+				uploaded = append(uploaded, m.To+"@"+m.CustomFields[CampaignChunkField])
+			}
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{}})
+		case r.Method == http.MethodPost && r.URL.Path == "/messages/v4/groups/g1/send":
+			_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	cp := NewFileCheckpoint(filepath.Join(t.TempDir(), "campaign.json"))
+	if err := cp.Save(context.Background(), CheckpointState{GroupID: "g1", ChunkSize: 2, Completed: []int{0}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	c, err := svc.ResumeCampaign(context.Background(), CampaignOptions{Checkpoint: cp, ChunkSize: 100})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	for _, to := range []string{"010", "011", "012", "013", "014"} {
+		if err := c.Add(context.Background(), messages.Message{To: to}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	if _, err := c.Send(context.Background()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(uploaded) != 1 || uploaded[0] != "014@4" {
+		t.Fatalf("expected only the last chunk to be uploaded, got %v", uploaded)
+	}
+	st, err := cp.Load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if st.GroupID != "" {
+		t.Fatalf("checkpoint should be cleared after send, got %+v", st)
+	}
+}
+
+func TestCampaign_RecordsCheckpoint(t *testing.T) {
+	f := &fakeGroupServer{}
+	ts := httptest.NewServer(f.handler(t))
+	defer ts.Close()
+
+	cp := &MemoryCheckpoint{}
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	c := svc.NewCampaign(CampaignOptions{ChunkSize: 1, Concurrency: 1, Checkpoint: cp})
+	for i := 0; i < 2; i++ {
+		if err := c.Add(context.Background(), messages.Message{To: "010"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	c.wg.Wait()
+	st, _ := cp.Load(context.Background())
+	if st.GroupID != "g1" || st.ChunkSize != 1 || len(st.Completed) != 2 {
+		t.Fatalf("unexpected checkpoint: %+v", st)
+	}
+}
+
+func TestCampaign_KeepsCheckpointOnTransientFailure(t *testing.T) {
+	f := &fakeGroupServer{failAdds: true}
+	ts := httptest.NewServer(f.handler(t))
+	defer ts.Close()
+
+	cp := &MemoryCheckpoint{}
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	c := svc.NewCampaign(CampaignOptions{ChunkSize: 1, Checkpoint: cp})
+	_ = c.Add(context.Background(), messages.Message{To: "010"})
+	_, err := c.Send(context.Background())
+	var ce *CampaignError
+	if !errors.As(err, &ce) || !ce.Resumable {
+		t.Fatalf("expected a resumable CampaignError, got %v", err)
+	}
+	if f.sent || f.removed {
+		t.Fatalf("sent=%v removed=%v", f.sent, f.removed)
+	}
+	if st, _ := cp.Load(context.Background()); st.GroupID != "g1" {
+		t.Fatalf("checkpoint cleared: %+v", st)
+	}
+}
@@ -0,0 +1,9 @@
+package groups
+
+import "github.com/solapi/solapi-go/v2/messages"
+
+// SetDefaults replaces the defaults applied by Create and AddMessages. It
+// must not be called concurrently with other calls.
+func (s *Service) SetDefaults(d messages.Defaults) {
+	s.defaults = d
+}
@@ -0,0 +1,113 @@
+package groups
+
+import (
+	"context"
+
+	"github.com/solapi/solapi-go/v2/idempotency"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// maxResend bounds the re-sends of a call after network errors.
+const maxResend = 3
+
+// SetIdempotencyStore sets the store of responses for calls made with an
+// IdempotencyKey. It must not be called concurrently with other calls.
+func (s *Service) SetIdempotencyStore(store idempotency.Store) {
+	s.idem = store
+}
+
+// SetIdempotencyLookback sets how many of the latest groups Create searches
+// for an earlier attempt after a network error. It must not be called
+// concurrently with other calls.
+func (s *Service) SetIdempotencyLookback(l idempotency.Lookback) {
+	s.lookback = l
+}
+
+// retryUnknown calls fn, and after a network error calls find to check
+// whether the earlier attempt took effect before calling fn again.
+func retryUnknown[T any](ctx context.Context, fn func() (T, error), find func() (T, bool, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := fn()
+		if err == nil || attempt == maxResend || !idempotency.UnknownOutcome(ctx, err) {
+			return res, err
+		}
+		prior, found, ferr := find()
+		if ferr != nil {
+			// calling again could duplicate; report the original failure
+			return res, err
+		}
+		if found {
+			return prior, nil
+		}
+	}
+}
+
+// tagFields returns a copy of fields carrying key.
+func tagFields(fields map[string]string, key string) map[string]string {
+	out := make(map[string]string, len(fields)+1)
+	for k, v := range fields {
+		out[k] = v
+	}
+	out[idempotency.CustomField] = key
+	return out
+}
+
+// findCreated looks for a group tagged with key among the latest ones.
+func (s *Service) findCreated(ctx context.Context, key string) (CreateGroupResponse, bool, error) {
+	left := s.lookback.GroupsOrDefault()
+	q := ListGroupsQuery{Limit: left}
+	for left > 0 {
+		list, err := s.ListGroups(ctx, q)
+		if err != nil {
+			return CreateGroupResponse{}, false, err
+		}
+		for id, g := range list.GroupList {
+			if g.CustomFields[idempotency.CustomField] != key && g.GroupInfo.CustomFields[idempotency.CustomField] != key {
+				continue
+			}
+			if g.GroupID != "" {
+				id = g.GroupID
+			}
+			return CreateGroupResponse{GroupID: id, GroupInfo: g.GroupInfo, Recovered: true}, true, nil
+		}
+		left -= len(list.GroupList)
+		if len(list.GroupList) == 0 || list.NextKey == "" || list.NextKey == q.StartKey {
+			break
+		}
+		q.StartKey, q.Limit = list.NextKey, left
+	}
+	return CreateGroupResponse{}, false, nil
+}
+
+// findAdded reports whether the group holds messages tagged with key.
+func (s *Service) findAdded(ctx context.Context, groupId, key string) (GroupActionResponse, bool, error) {
+	q := ListMessagesQuery{}
+	for {
+		page, err := s.ListMessages(ctx, groupId, q)
+		if err != nil {
+			return GroupActionResponse{}, false, err
+		}
+		for _, m := range page.MessageList {
+			if m.CustomFields[idempotency.CustomField] == key {
+				return GroupActionResponse{Recovered: true}, true, nil
+			}
+		}
+		if page.NextKey == "" || page.NextKey == q.StartKey {
+			return GroupActionResponse{}, false, nil
+		}
+		q.StartKey = page.NextKey
+	}
+}
+
+// findSent reports whether the group has left the PENDING status, which
+// means an earlier send request reached the server.
+func (s *Service) findSent(ctx context.Context, groupId string) (messages.DetailGroupMessageResponse, bool, error) {
+	g, err := s.GetGroup(ctx, groupId)
+	if err != nil {
+		return messages.DetailGroupMessageResponse{}, false, err
+	}
+	if g.GroupInfo.Status == "" || g.GroupInfo.Status == "PENDING" {
+		return messages.DetailGroupMessageResponse{}, false, nil
+	}
+	return messages.DetailGroupMessageResponse{GroupInfo: g.GroupInfo, GroupID: groupId, Status: g.GroupInfo.Status, Recovered: true}, true, nil
+}
@@ -0,0 +1,166 @@
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/idempotency"
+	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func dropConnection(t *testing.T, w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestGroups_Send_ChecksStatusAfterNetworkError(t *testing.T) {
+	posts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+			dropConnection(t, w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupId": "g1", "groupInfo": map[string]any{"status": "SENDING"}})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	res, err := svc.Send(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if posts != 1 || !res.Recovered || res.GroupID != "g1" || res.Status != "SENDING" {
+		t.Fatalf("posts=%d res=%+v", posts, res)
+	}
+}
+
+func TestGroups_Create_IdempotencyKey(t *testing.T) {
+	posts := 0
+	var fields map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(map[string]any{"groupList": map[string]any{
+				"G-OLD": map[string]any{"groupId": "G-OLD"},
+				"G1":    map[string]any{"groupId": "G1", "customFields": fields},
+			}})
+			return
+		}
+		posts++
+		var body struct {
+			CustomFields map[string]string `json:"customFields"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		fields = body.CustomFields
+		dropConnection(t, w)
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	svc.SetIdempotencyStore(idempotency.NewMemoryStore(0))
+	opt := CreateGroupOptions{CustomFields: map[string]string{"batch": "7"}, IdempotencyKey: "batch-7"}
+	for i := 0; i < 2; i++ {
+		res, err := svc.Create(context.Background(), opt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.GroupID != "G1" {
+			t.Fatalf("unexpected group: %+v", res)
+		}
+	}
+	if posts != 1 {
+		t.Fatalf("created %d times, want 1", posts)
+	}
+	if fields["batch"] != "7" || fields[idempotency.CustomField] != "batch-7" {
+		t.Fatalf("group not tagged: %v", fields)
+	}
+	if _, ok := opt.CustomFields[idempotency.CustomField]; ok {
+		t.Fatal("caller's custom fields modified")
+	}
+}
+
+func TestGroups_Create_IdempotencyLookback(t *testing.T) {
+	var fields map[string]string
+	var limits []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var body struct {
+				CustomFields map[string]string `json:"customFields"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			fields = body.CustomFields
+			dropConnection(t, w)
+			return
+		}
+		limits = append(limits, r.URL.Query().Get("limit"))
+		if r.URL.Query().Get("startKey") == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"nextKey": "P2", "groupList": map[string]any{
+				"G-NEW1": map[string]any{"groupId": "G-NEW1"},
+				"G-NEW2": map[string]any{"groupId": "G-NEW2"},
+			}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupList": map[string]any{
+			"G1": map[string]any{"groupId": "G1", "customFields": fields},
+		}})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	svc.SetIdempotencyLookback(idempotency.Lookback{Groups: 50})
+	res, err := svc.Create(context.Background(), CreateGroupOptions{IdempotencyKey: "batch-7"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Recovered || res.GroupID != "G1" {
+		t.Fatalf("unexpected group: %+v", res)
+	}
+	if len(limits) != 2 || limits[0] != "50" || limits[1] != "48" {
+		t.Fatalf("unexpected page limits: %v", limits)
+	}
+}
+
+func TestGroups_AddMessages_IdempotencyKey(t *testing.T) {
+	puts := 0
+	var added []messages.Message
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			list := map[string]any{}
+			for i, m := range added {
+				list[string(rune('A'+i))] = m
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messageList": list})
+			return
+		}
+		puts++
+		var body AddGroupMessagesRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		added = body.Messages
+		dropConnection(t, w)
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	msgs := []messages.Message{{To: "01011111111", From: "029999", Text: "a"}}
+	res, err := svc.AddMessages(context.Background(), "g1", AddGroupMessagesRequest{Messages: msgs, IdempotencyKey: "chunk-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if puts != 1 || !res.Recovered {
+		t.Fatalf("puts=%d res=%+v", puts, res)
+	}
+	if msgs[0].CustomFields != nil {
+		t.Fatal("caller's messages modified")
+	}
+}
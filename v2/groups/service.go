@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"net/url"
 	"runtime"
+	"time"
 
 	"net/http"
 
+	"github.com/solapi/solapi-go/v2/idempotency"
+	"github.com/solapi/solapi-go/v2/instrumentation"
 	"github.com/solapi/solapi-go/v2/internal/auth"
 	"github.com/solapi/solapi-go/v2/internal/transport"
 	"github.com/solapi/solapi-go/v2/messages"
@@ -16,11 +19,15 @@ import (
 // Service exposes group-related endpoints
 type Service struct {
 	baseURL    string
-	creds      auth.AuthenticationParameter
+	creds      auth.CredentialsProvider
 	httpClient *http.Client
+	filters    []messages.RecipientFilter
+	idem       idempotency.Store
+	lookback   idempotency.Lookback
+	defaults   messages.Defaults
 }
 
-func NewService(baseURL string, creds auth.AuthenticationParameter) *Service {
+func NewService(baseURL string, creds auth.CredentialsProvider) *Service {
 	return &Service{baseURL: baseURL, creds: creds, httpClient: http.DefaultClient}
 }
 
@@ -28,6 +35,12 @@ type CreateGroupOptions struct {
 	AllowDuplicates bool
 	AppId           string
 	CustomFields    map[string]string
+
+	// IdempotencyKey makes Create safe to repeat. The group is tagged with it
+	// in the idempotency.CustomField custom field, a retry after a network
+	// error first looks for the tagged group, and with an idempotency store a
+	// repeated key returns the stored response.
+	IdempotencyKey string
 }
 
 // Create POST /messages/v4/groups
@@ -39,6 +52,9 @@ func (s *Service) Create(ctx context.Context, opt CreateGroupOptions) (CreateGro
 		AppId           string            `json:"appId,omitempty"`
 		CustomFields    map[string]string `json:"customFields,omitempty"`
 	}
+	if opt.AppId == "" {
+		opt.AppId = s.defaults.AppId
+	}
 	b := body{
 		SDKVersion:      "go/2.0.0",
 		OSPlatform:      runtime.GOOS + " | " + runtime.Version(),
@@ -47,17 +63,64 @@ func (s *Service) Create(ctx context.Context, opt CreateGroupOptions) (CreateGro
 		CustomFields:    opt.CustomFields,
 	}
 	urlStr := fmt.Sprintf("%s/messages/v4/groups", s.baseURL)
-	req := transport.DefaultRequest{URL: urlStr, Method: "POST"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
-	return transport.FetchJSON[body, CreateGroupResponse](ctx, s.creds, req, &b)
+	req := transport.DefaultRequest{URL: urlStr, Method: "POST", Operation: "groups.create"}
+	if opt.IdempotencyKey == "" {
+		return transport.FetchJSON[body, CreateGroupResponse](s.withTransport(ctx), s.creds, req, &b)
+	}
+	b.CustomFields = tagFields(opt.CustomFields, opt.IdempotencyKey)
+	req.NoNetworkRetry = true
+	return idempotency.Do(ctx, s.idem, "groups.create", opt.IdempotencyKey, func(ctx context.Context) (CreateGroupResponse, error) {
+		return retryUnknown(ctx, func() (CreateGroupResponse, error) {
+			return transport.FetchJSON[body, CreateGroupResponse](s.withTransport(ctx), s.creds, req, &b)
+		}, func() (CreateGroupResponse, bool, error) {
+			return s.findCreated(ctx, opt.IdempotencyKey)
+		})
+	})
+}
+
+// SetFilters replaces the recipient filters applied by AddMessages. It must
+// not be called concurrently with other calls.
+func (s *Service) SetFilters(filters ...messages.RecipientFilter) {
+	s.filters = filters
 }
 
 // AddMessages PUT /messages/v4/groups/{groupId}/messages
+//
+// Recipient filters run first. When they remove every recipient, no request
+// is made and messages.ErrNoRecipients is returned with the report, as
+// messages.Service.SendManyDetail does.
 func (s *Service) AddMessages(ctx context.Context, groupId string, reqBody AddGroupMessagesRequest) (GroupActionResponse, error) {
+	msgs, filtered, err := messages.FilterRecipients(ctx, s.defaults.Apply(reqBody.Messages), s.filters...)
+	if err != nil {
+		return GroupActionResponse{Filtered: filtered}, err
+	}
+	if len(msgs) == 0 && len(reqBody.Messages) > 0 {
+		return GroupActionResponse{Filtered: filtered}, messages.ErrNoRecipients
+	}
+	reqBody.Messages = msgs
 	urlStr := fmt.Sprintf("%s/messages/v4/groups/%s/messages", s.baseURL, groupId)
-	req := transport.DefaultRequest{URL: urlStr, Method: "PUT"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
-	return transport.FetchJSON[AddGroupMessagesRequest, GroupActionResponse](ctx, s.creds, req, &reqBody)
+	req := transport.DefaultRequest{URL: urlStr, Method: "PUT", Operation: "groups.add_messages", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}, Messages: len(reqBody.Messages)}
+	var res GroupActionResponse
+	if key := reqBody.IdempotencyKey; key == "" {
+		res, err = transport.FetchJSON[AddGroupMessagesRequest, GroupActionResponse](s.withTransport(ctx), s.creds, req, &reqBody)
+	} else {
+		tagged := make([]messages.Message, len(reqBody.Messages))
+		for i, m := range reqBody.Messages {
+			m.CustomFields = tagFields(m.CustomFields, key)
+			tagged[i] = m
+		}
+		reqBody.Messages = tagged
+		req.NoNetworkRetry = true
+		res, err = idempotency.Do(ctx, s.idem, "groups.add_messages", groupId+":"+key, func(ctx context.Context) (GroupActionResponse, error) {
+			return retryUnknown(ctx, func() (GroupActionResponse, error) {
+				return transport.FetchJSON[AddGroupMessagesRequest, GroupActionResponse](s.withTransport(ctx), s.creds, req, &reqBody)
+			}, func() (GroupActionResponse, bool, error) {
+				return s.findAdded(ctx, groupId, key)
+			})
+		})
+	}
+	res.Filtered = filtered
+	return res, err
 }
 
 // ListMessages GET /messages/v4/groups/{groupId}/messages
@@ -73,33 +136,48 @@ func (s *Service) ListMessages(ctx context.Context, groupId string, q ListMessag
 	if enc := values.Encode(); enc != "" {
 		urlStr += "?" + enc
 	}
-	req := transport.DefaultRequest{URL: urlStr, Method: "GET"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	req := transport.DefaultRequest{URL: urlStr, Method: "GET", Operation: "groups.list_messages", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}}
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[struct{}, messages.MessageListResponse](ctx, s.creds, req, nil)
 }
 
 // Send POST /messages/v4/groups/{groupId}/send
+//
+// A group is sent at most once, so after a network error Send checks the
+// group's status before sending again.
 func (s *Service) Send(ctx context.Context, groupId string) (messages.DetailGroupMessageResponse, error) {
 	urlStr := fmt.Sprintf("%s/messages/v4/groups/%s/send", s.baseURL, groupId)
-	req := transport.DefaultRequest{URL: urlStr, Method: "POST"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
-	return transport.FetchJSON[struct{}, messages.DetailGroupMessageResponse](ctx, s.creds, req, nil)
+	req := transport.DefaultRequest{URL: urlStr, Method: "POST", Operation: "groups.send", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}, NoNetworkRetry: true}
+	return retryUnknown(ctx, func() (messages.DetailGroupMessageResponse, error) {
+		return transport.FetchJSON[struct{}, messages.DetailGroupMessageResponse](s.withTransport(ctx), s.creds, req, nil)
+	}, func() (messages.DetailGroupMessageResponse, bool, error) {
+		return s.findSent(ctx, groupId)
+	})
 }
 
 // Reserve POST /messages/v4/groups/{groupId}/schedule
 func (s *Service) Reserve(ctx context.Context, groupId string, scheduledDate string) (messages.DetailGroupMessageResponse, error) {
 	urlStr := fmt.Sprintf("%s/messages/v4/groups/%s/schedule", s.baseURL, groupId)
-	req := transport.DefaultRequest{URL: urlStr, Method: "POST"}
+	req := transport.DefaultRequest{URL: urlStr, Method: "POST", Operation: "groups.reserve", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}}
 	body := ScheduleRequest{ScheduledDate: scheduledDate}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[ScheduleRequest, messages.DetailGroupMessageResponse](ctx, s.creds, req, &body)
 }
 
+// ReserveAt schedules the group for t after validating it with
+// messages.ValidateSchedule. The date is sent in UTC ISO-8601 form.
+func (s *Service) ReserveAt(ctx context.Context, groupId string, t time.Time) (messages.DetailGroupMessageResponse, error) {
+	if err := messages.ValidateSchedule(t); err != nil {
+		return messages.DetailGroupMessageResponse{}, err
+	}
+	return s.Reserve(ctx, groupId, messages.FormatDate(t))
+}
+
 // CancelReservation DELETE /messages/v4/groups/{groupId}/schedule
 func (s *Service) CancelReservation(ctx context.Context, groupId string) (messages.DetailGroupMessageResponse, error) {
 	urlStr := fmt.Sprintf("%s/messages/v4/groups/%s/schedule", s.baseURL, groupId)
-	req := transport.DefaultRequest{URL: urlStr, Method: "DELETE"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	req := transport.DefaultRequest{URL: urlStr, Method: "DELETE", Operation: "groups.cancel_reservation", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}}
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[struct{}, messages.DetailGroupMessageResponse](ctx, s.creds, req, nil)
 }
 
@@ -116,32 +194,32 @@ func (s *Service) ListGroups(ctx context.Context, q ListGroupsQuery) (ListGroups
 	if enc := values.Encode(); enc != "" {
 		urlStr += "?" + enc
 	}
-	req := transport.DefaultRequest{URL: urlStr, Method: "GET"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	req := transport.DefaultRequest{URL: urlStr, Method: "GET", Operation: "groups.list"}
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[struct{}, ListGroupsResponse](ctx, s.creds, req, nil)
 }
 
 // GetGroup GET /messages/v4/groups/{groupId}
 func (s *Service) GetGroup(ctx context.Context, groupId string) (GroupResponse, error) {
 	urlStr := fmt.Sprintf("%s/messages/v4/groups/%s", s.baseURL, groupId)
-	req := transport.DefaultRequest{URL: urlStr, Method: "GET"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	req := transport.DefaultRequest{URL: urlStr, Method: "GET", Operation: "groups.get", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}}
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[struct{}, GroupResponse](ctx, s.creds, req, nil)
 }
 
 // RemoveMessages DELETE /messages/v4/groups/{groupId}/messages
 func (s *Service) RemoveMessages(ctx context.Context, groupId string, messageIds []string) (GroupActionResponse, error) {
 	urlStr := fmt.Sprintf("%s/messages/v4/groups/%s/messages", s.baseURL, groupId)
-	req := transport.DefaultRequest{URL: urlStr, Method: "DELETE"}
+	req := transport.DefaultRequest{URL: urlStr, Method: "DELETE", Operation: "groups.remove_messages", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}}
 	body := RemoveGroupMessagesRequest{MessageIDs: messageIds}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[RemoveGroupMessagesRequest, GroupActionResponse](ctx, s.creds, req, &body)
 }
 
 // RemoveGroup DELETE /messages/v4/groups/{groupId}
 func (s *Service) RemoveGroup(ctx context.Context, groupId string) (GroupResponse, error) {
 	urlStr := fmt.Sprintf("%s/messages/v4/groups/%s", s.baseURL, groupId)
-	req := transport.DefaultRequest{URL: urlStr, Method: "DELETE"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	req := transport.DefaultRequest{URL: urlStr, Method: "DELETE", Operation: "groups.remove", Attrs: []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, groupId)}}
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[struct{}, GroupResponse](ctx, s.creds, req, nil)
 }
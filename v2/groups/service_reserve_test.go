@@ -0,0 +1,42 @@
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestGroups_ReserveAt_EncodesUTC(t *testing.T) {
+	var body ScheduleRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/messages/v4/groups/g1/schedule" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{}})
+	}))
+	defer ts.Close()
+
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	if _, err := svc.ReserveAt(context.Background(), "g1", at); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.ScheduledDate != at.UTC().Format(time.RFC3339) {
+		t.Fatalf("scheduledDate = %q", body.ScheduledDate)
+	}
+}
+
+func TestGroups_ReserveAt_RejectsPast(t *testing.T) {
+	svc := NewService("http://invalid", auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	if _, err := svc.ReserveAt(context.Background(), "g1", time.Now().Add(-time.Hour)); err == nil {
+		t.Fatalf("expected error for past schedule")
+	}
+}
@@ -11,16 +11,32 @@ type CreateGroupRequest struct {
 type CreateGroupResponse struct {
 	GroupID   string             `json:"groupId"`
 	GroupInfo messages.GroupInfo `json:"groupInfo,omitempty"`
+
+	// Recovered is set when the group was found on the server after a
+	// network error; only GroupID is filled in then.
+	Recovered bool `json:"-"`
 }
 
 type AddGroupMessagesRequest struct {
 	Messages        []messages.Message `json:"messages"`
 	AllowDuplicates *bool              `json:"allowDuplicates,omitempty"`
+
+	// IdempotencyKey makes the call safe to repeat, like
+	// messages.SendRequest.IdempotencyKey.
+	IdempotencyKey string `json:"-"`
 }
 
 type GroupActionResponse struct {
 	GroupInfo         messages.GroupInfo       `json:"groupInfo"`
 	FailedMessageList []messages.FailedMessage `json:"failedMessageList"`
+
+	// Filtered reports recipients dropped or rewritten by recipient filters.
+	// It is not part of the API response.
+	Filtered []messages.FilteredRecipient `json:"-"`
+
+	// Recovered is set when the messages were found in the group after a
+	// network error; GroupInfo is empty then.
+	Recovered bool `json:"-"`
 }
 
 type ListMessagesQuery struct {
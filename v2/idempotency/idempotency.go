@@ -0,0 +1,207 @@
+// Package idempotency prevents duplicate sends when a call is repeated.
+//
+// A call made with an idempotency key stores the API response in a Store;
+// repeating the call with the same key returns the stored response instead
+// of sending again. Independently of the store, the SDK tags what it creates
+// with the key in the CustomField custom field, so a retry after a network
+// error whose outcome is unknown first looks for the earlier attempt on the
+// server before sending again.
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CustomField is the custom field carrying the idempotency key on messages
+// and groups created with one.
+const CustomField = "idempotencyKey"
+
+// Default limits of the search for an earlier attempt.
+const (
+	DefaultLookbackWindow = 10 * time.Minute
+	DefaultLookbackGroups = 20
+)
+
+// Lookback bounds the search on the server for an earlier attempt after a
+// network error. An attempt older than the bounds is not found and the call
+// is sent again, so raise them for callers that retry after long outages or
+// create many groups.
+type Lookback struct {
+	// Window is how far back sent messages are searched. Zero means
+	// DefaultLookbackWindow.
+	Window time.Duration
+	// Groups is how many of the latest groups are searched for a created
+	// group. Zero means DefaultLookbackGroups.
+	Groups int
+}
+
+// WindowOrDefault returns Window, or DefaultLookbackWindow when it is zero.
+func (l Lookback) WindowOrDefault() time.Duration {
+	if l.Window > 0 {
+		return l.Window
+	}
+	return DefaultLookbackWindow
+}
+
+// GroupsOrDefault returns Groups, or DefaultLookbackGroups when it is zero.
+func (l Lookback) GroupsOrDefault() int {
+	if l.Groups > 0 {
+		return l.Groups
+	}
+	return DefaultLookbackGroups
+}
+
+// Store persists responses by key. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Get returns the value stored under key and whether it exists.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Put stores value under key.
+	Put(ctx context.Context, key string, value []byte) error
+}
+
+// MemoryStore is an in-process Store. Keys expire after the TTL passed to
+// NewMemoryStore; a zero TTL keeps them until the process exits.
+type MemoryStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu sync.Mutex
+	m  map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, now: time.Now, m: make(map[string]memoryEntry)}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !e.expires.IsZero() && !s.now().Before(e.expires) {
+		delete(s.m, key)
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+// Put implements Store. Expired entries are swept on every call.
+func (s *MemoryStore) Put(_ context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for k, e := range s.m {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			delete(s.m, k)
+		}
+	}
+	e := memoryEntry{value: append([]byte(nil), value...)}
+	if s.ttl > 0 {
+		e.expires = now.Add(s.ttl)
+	}
+	s.m[key] = e
+	return nil
+}
+
+// Len returns the number of stored keys, including expired ones not yet
+// swept.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.m)
+}
+
+// Do returns the response stored for op and key, or calls fn and stores its
+// result when fn succeeds. Keys are scoped by op so one key can be reused
+// across operations. Concurrent calls with the same op and key in this
+// process run one at a time, so the later ones see the stored response.
+// With a nil store or an empty key fn is called directly.
+//
+// Once fn has succeeded its result is returned even if it cannot be stored:
+// the call has taken effect, and reporting it as failed would invite the
+// very repeat the key guards against. A later repeat then falls back to
+// the lookup of the tagged attempt on the server.
+func Do[T any](ctx context.Context, store Store, op, key string, fn func(context.Context) (T, error)) (T, error) {
+	if store == nil || key == "" {
+		return fn(ctx)
+	}
+	var zero T
+	k := op + ":" + key
+	unlock := locks.lock(k)
+	defer unlock()
+
+	if b, ok, err := store.Get(ctx, k); err != nil {
+		return zero, err
+	} else if ok {
+		var res T
+		if err := json.Unmarshal(b, &res); err != nil {
+			return zero, err
+		}
+		return res, nil
+	}
+	res, err := fn(ctx)
+	if err != nil {
+		return res, err
+	}
+	if b, err := json.Marshal(res); err == nil {
+		_ = store.Put(ctx, k, b)
+	}
+	return res, nil
+}
+
+// UnknownOutcome reports whether err leaves it unknown if the server
+// processed the request: the request failed in transit and ctx is still
+// live, so the call may be retried after checking for an earlier attempt.
+func UnknownOutcome(ctx context.Context, err error) bool {
+	var uerr *url.Error
+	return ctx.Err() == nil && errors.As(err, &uerr)
+}
+
+// keyedMutex serializes calls per key.
+type keyedMutex struct {
+	mu sync.Mutex
+	m  map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+var locks = keyedMutex{m: make(map[string]*keyedLock)}
+
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	l, ok := k.m[key]
+	if !ok {
+		l = &keyedLock{}
+		k.m[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		k.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(k.m, key)
+		}
+		k.mu.Unlock()
+	}
+}
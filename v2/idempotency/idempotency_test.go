@@ -0,0 +1,112 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreExpires(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := NewMemoryStore(time.Minute)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, _ := s.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Fatalf("got %q %v", v, ok)
+	}
+	now = now.Add(time.Minute)
+	if _, ok, _ := s.Get(ctx, "a"); ok {
+		t.Fatal("entry should have expired")
+	}
+	if s.Len() != 0 {
+		t.Fatalf("expired entry kept: %d", s.Len())
+	}
+}
+
+type result struct {
+	ID string `json:"id"`
+}
+
+func TestDoStoresSuccessOnly(t *testing.T) {
+	s := NewMemoryStore(0)
+	ctx := context.Background()
+	calls := 0
+	fail := func(context.Context) (result, error) {
+		calls++
+		return result{}, errors.New("boom")
+	}
+	ok := func(context.Context) (result, error) {
+		calls++
+		return result{ID: "G1"}, nil
+	}
+
+	if _, err := Do(ctx, s, "op", "k", fail); err == nil {
+		t.Fatal("expected error")
+	}
+	for i := 0; i < 2; i++ {
+		res, err := Do(ctx, s, "op", "k", ok)
+		if err != nil || res.ID != "G1" {
+			t.Fatalf("res=%+v err=%v", res, err)
+		}
+	}
+	if _, err := Do(ctx, s, "other", "k", ok); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls=%d, want 3", calls)
+	}
+	if _, err := Do(ctx, nil, "op", "k", ok); err != nil || calls != 4 {
+		t.Fatalf("nil store should call through: calls=%d err=%v", calls, err)
+	}
+}
+
+// failingStore loses every response.
+type failingStore struct{ puts int }
+
+func (s *failingStore) Get(context.Context, string) ([]byte, bool, error) { return nil, false, nil }
+
+func (s *failingStore) Put(context.Context, string, []byte) error {
+	s.puts++
+	return errors.New("disk full")
+}
+
+func TestDoReturnsResultWhenStoreFails(t *testing.T) {
+	s := &failingStore{}
+	res, err := Do(context.Background(), s, "op", "k", func(context.Context) (result, error) {
+		return result{ID: "G1"}, nil
+	})
+	if err != nil || res.ID != "G1" || s.puts != 1 {
+		t.Fatalf("res=%+v err=%v puts=%d", res, err, s.puts)
+	}
+}
+
+func TestDoSerializesSameKey(t *testing.T) {
+	s := NewMemoryStore(0)
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = Do(context.Background(), s, "op", "k", func(context.Context) (result, error) {
+				calls.Add(1)
+				time.Sleep(time.Millisecond)
+				return result{ID: "G1"}, nil
+			})
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("calls=%d, want 1", calls.Load())
+	}
+	if len(locks.m) != 0 {
+		t.Fatalf("locks leaked: %d", len(locks.m))
+	}
+}
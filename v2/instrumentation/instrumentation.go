@@ -0,0 +1,72 @@
+// Package instrumentation defines the hooks the SDK calls around every API
+// request so tracing and metrics backends can be plugged in without the SDK
+// depending on them. See the otelsolapi module for an OpenTelemetry adapter.
+package instrumentation
+
+import (
+	"context"
+	"time"
+)
+
+// Attribute keys set by the SDK.
+const (
+	AttrGroupID      = "solapi.group_id"
+	AttrMessageCount = "solapi.message_count"
+	AttrRetryCount   = "solapi.retry_count"
+	AttrErrorCode    = "solapi.error_code"
+	AttrHTTPMethod   = "http.request.method"
+	AttrHTTPStatus   = "http.response.status_code"
+)
+
+// Attribute is a key-value pair describing a call. Value is a string, int,
+// int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: value} }
+
+// Span is a single API call, covering all of its retries.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// End finishes the span; err is the error returned to the caller, if any.
+	End(err error)
+}
+
+// Instrumentation receives spans and metrics for every API call. Operation
+// names have the form "<service>.<method>", e.g. "messages.send_many_detail".
+// Implementations must be safe for concurrent use.
+type Instrumentation interface {
+	// StartSpan starts a span. The returned context is used for the HTTP
+	// request, so trace context propagated by the HTTP client is preserved.
+	StartSpan(ctx context.Context, operation string, attrs ...Attribute) (context.Context, Span)
+	// RecordLatency records the duration of a finished call.
+	RecordLatency(ctx context.Context, operation string, d time.Duration, attrs ...Attribute)
+	// AddMessages counts messages submitted by a successful send or group
+	// append call. Messages the API refused to register are included; see
+	// the response's failed message list for those.
+	AddMessages(ctx context.Context, operation string, n int, attrs ...Attribute)
+}
+
+// Nop is an Instrumentation that does nothing. Embed it to implement only
+// some of the hooks.
+type Nop struct{}
+
+func (Nop) StartSpan(ctx context.Context, operation string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+func (Nop) RecordLatency(ctx context.Context, operation string, d time.Duration, attrs ...Attribute) {
+}
+
+func (Nop) AddMessages(ctx context.Context, operation string, n int, attrs ...Attribute) {}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(attrs ...Attribute) {}
+func (nopSpan) End(err error)                    {}
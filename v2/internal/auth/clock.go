@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock corrects the local time used for signing by an offset measured
+// against the server. The zero value applies no correction and is safe for
+// concurrent use.
+type Clock struct {
+	offset atomic.Int64
+}
+
+// Now returns the corrected current time in UTC.
+func (c *Clock) Now() time.Time {
+	return time.Now().Add(c.Offset()).UTC()
+}
+
+// Offset returns the current correction (server time minus local time).
+func (c *Clock) Offset() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.offset.Load())
+}
+
+// SetOffset replaces the correction.
+func (c *Clock) SetOffset(d time.Duration) {
+	c.offset.Store(int64(d))
+}
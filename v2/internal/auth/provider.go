@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CredentialsProvider supplies the key pair used to sign a request. It is
+// consulted once per request attempt and must be safe for concurrent use.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (AuthenticationParameter, error)
+}
+
+// Credentials lets a static AuthenticationParameter act as a provider.
+func (p AuthenticationParameter) Credentials(ctx context.Context) (AuthenticationParameter, error) {
+	return p, nil
+}
+
+// invalidator is implemented by providers that cache credentials and can be
+// told to drop them, e.g. after the API rejected them.
+type invalidator interface {
+	Invalidate()
+}
+
+// Invalidate drops cached credentials of p, if it caches any.
+func Invalidate(p CredentialsProvider) {
+	if inv, ok := p.(invalidator); ok {
+		inv.Invalidate()
+	}
+}
+
+// BuildAuthorizationHeaderFrom resolves credentials from p and builds the
+// Authorization header with a fresh salt and the current date.
+func BuildAuthorizationHeaderFrom(ctx context.Context, p CredentialsProvider) (string, error) {
+	params, err := p.Credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	return BuildAuthorizationHeader(params)
+}
+
+// ProviderFunc adapts a function, e.g. a secret manager lookup, to a provider.
+type ProviderFunc func(ctx context.Context) (AuthenticationParameter, error)
+
+func (f ProviderFunc) Credentials(ctx context.Context) (AuthenticationParameter, error) {
+	return f(ctx)
+}
+
+// Default environment variables read by EnvProvider.
+const (
+	EnvAPIKey    = "SOLAPI_API_KEY"
+	EnvAPISecret = "SOLAPI_API_SECRET"
+)
+
+// EnvProvider reads credentials from environment variables on every call.
+// Empty names fall back to EnvAPIKey and EnvAPISecret.
+type EnvProvider struct {
+	KeyVar    string
+	SecretVar string
+}
+
+func (e EnvProvider) Credentials(ctx context.Context) (AuthenticationParameter, error) {
+	keyVar, secretVar := e.KeyVar, e.SecretVar
+	if keyVar == "" {
+		keyVar = EnvAPIKey
+	}
+	if secretVar == "" {
+		secretVar = EnvAPISecret
+	}
+	p := AuthenticationParameter{ApiKey: os.Getenv(keyVar), ApiSecret: os.Getenv(secretVar)}
+	if p.ApiKey == "" {
+		return p, fmt.Errorf("%w: %s is not set", ErrInvalidAPIKey, keyVar)
+	}
+	if p.ApiSecret == "" {
+		return p, fmt.Errorf("%w: %s is not set", ErrInvalidAPISecret, secretVar)
+	}
+	return p, nil
+}
+
+// FileProvider reads credentials from a JSON file such as
+// {"apiKey": "...", "apiSecret": "..."} and reloads it whenever its
+// modification time or size changes.
+type FileProvider struct {
+	Path string
+
+	mu      sync.RWMutex
+	modTime time.Time
+	size    int64
+	cached  AuthenticationParameter
+}
+
+// NewFileProvider returns a FileProvider reading path.
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{Path: path}
+}
+
+func (f *FileProvider) Credentials(ctx context.Context) (AuthenticationParameter, error) {
+	fi, err := os.Stat(f.Path)
+	if err != nil {
+		return AuthenticationParameter{}, err
+	}
+	f.mu.RLock()
+	fresh := f.cached.ApiKey != "" && fi.ModTime().Equal(f.modTime) && fi.Size() == f.size
+	cached := f.cached
+	f.mu.RUnlock()
+	if fresh {
+		return cached, nil
+	}
+
+	b, err := os.ReadFile(f.Path)
+	if err != nil {
+		return AuthenticationParameter{}, err
+	}
+	var v struct {
+		ApiKey    string `json:"apiKey"`
+		ApiSecret string `json:"apiSecret"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return AuthenticationParameter{}, fmt.Errorf("auth: parse %s: %w", f.Path, err)
+	}
+	p := AuthenticationParameter{ApiKey: v.ApiKey, ApiSecret: v.ApiSecret}
+	f.mu.Lock()
+	f.cached, f.modTime, f.size = p, fi.ModTime(), fi.Size()
+	f.mu.Unlock()
+	return p, nil
+}
+
+// Invalidate forces the next call to re-read the file.
+func (f *FileProvider) Invalidate() {
+	f.mu.Lock()
+	f.cached = AuthenticationParameter{}
+	f.mu.Unlock()
+}
+
+// CachingProvider caches the credentials of an underlying provider for TTL.
+// Concurrent callers share a single refresh.
+type CachingProvider struct {
+	Provider CredentialsProvider
+	TTL      time.Duration
+
+	mu      sync.Mutex
+	cached  AuthenticationParameter
+	expires time.Time
+}
+
+// NewCachingProvider wraps p with a cache of the given TTL.
+func NewCachingProvider(p CredentialsProvider, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{Provider: p, TTL: ttl}
+}
+
+func (c *CachingProvider) Credentials(ctx context.Context) (AuthenticationParameter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cached.ApiKey != "" && time.Now().Before(c.expires) {
+		return c.cached, nil
+	}
+	p, err := c.Provider.Credentials(ctx)
+	if err != nil {
+		return AuthenticationParameter{}, err
+	}
+	c.cached, c.expires = p, time.Now().Add(c.TTL)
+	return p, nil
+}
+
+// Invalidate drops the cached value and forwards to the underlying provider.
+func (c *CachingProvider) Invalidate() {
+	c.mu.Lock()
+	c.cached = AuthenticationParameter{}
+	c.mu.Unlock()
+	Invalidate(c.Provider)
+}
+
+// RotatingProvider holds credentials that can be replaced at runtime, e.g.
+// from a rotation hook. Requests already in flight keep the old pair.
+type RotatingProvider struct {
+	v atomic.Pointer[AuthenticationParameter]
+}
+
+// NewRotatingProvider returns a RotatingProvider holding p.
+func NewRotatingProvider(p AuthenticationParameter) *RotatingProvider {
+	r := &RotatingProvider{}
+	r.Rotate(p)
+	return r
+}
+
+// Rotate atomically replaces the credentials.
+func (r *RotatingProvider) Rotate(p AuthenticationParameter) {
+	r.v.Store(&p)
+}
+
+func (r *RotatingProvider) Credentials(ctx context.Context) (AuthenticationParameter, error) {
+	p := r.v.Load()
+	if p == nil {
+		return AuthenticationParameter{}, ErrInvalidAPIKey
+	}
+	return *p, nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileProvider_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(path, []byte(`{"apiKey":"k1","apiSecret":"s1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := NewFileProvider(path)
+	got, err := p.Credentials(context.Background())
+	if err != nil || got.ApiKey != "k1" {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"apiKey":"key2","apiSecret":"s2"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	_ = os.Chtimes(path, later, later)
+	got, err = p.Credentials(context.Background())
+	if err != nil || got.ApiKey != "key2" || got.ApiSecret != "s2" {
+		t.Fatalf("expected reloaded credentials, got %+v, %v", got, err)
+	}
+}
+
+func TestCachingProvider_CachesAndInvalidates(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	inner := ProviderFunc(func(ctx context.Context) (AuthenticationParameter, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return AuthenticationParameter{ApiKey: "k", ApiSecret: "s"}, nil
+	})
+	c := NewCachingProvider(inner, time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = c.Credentials(context.Background())
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("expected a single refresh, got %d", calls)
+	}
+	Invalidate(c)
+	_, _ = c.Credentials(context.Background())
+	if calls != 2 {
+		t.Fatalf("expected refresh after invalidate, got %d", calls)
+	}
+}
+
+func TestEnvProvider_MissingVariable(t *testing.T) {
+	t.Setenv("TEST_SOLAPI_KEY", "k")
+	_, err := EnvProvider{KeyVar: "TEST_SOLAPI_KEY", SecretVar: "TEST_SOLAPI_MISSING"}.Credentials(context.Background())
+	if !errors.Is(err, ErrInvalidAPISecret) || !strings.Contains(err.Error(), "TEST_SOLAPI_MISSING") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRotatingProvider_Rotate(t *testing.T) {
+	r := NewRotatingProvider(AuthenticationParameter{ApiKey: "old", ApiSecret: "s"})
+	r.Rotate(AuthenticationParameter{ApiKey: "new", ApiSecret: "s"})
+	got, _ := r.Credentials(context.Background())
+	if got.ApiKey != "new" {
+		t.Fatalf("expected rotated key, got %s", got.ApiKey)
+	}
+}
@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter_SpacesEventsAfterBurst(t *testing.T) {
+	l := New(20, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// two events come from the burst, the other two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("limiter did not wait, elapsed=%v", elapsed)
+	}
+}
+
+func TestLimiter_FailsFastWhenDeadlineTooSoon(t *testing.T) {
+	l := New(1, 1)
+	_ = l.Wait(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 5*time.Millisecond {
+		t.Fatalf("expected immediate failure")
+	}
+}
@@ -0,0 +1,129 @@
+// Package redact masks credentials and personal data in request and
+// response payloads before they are logged or stored.
+package redact
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Placeholder replaces redacted values.
+const Placeholder = "[REDACTED]"
+
+var (
+	// Korean phone numbers standing on their own, so that the digits inside
+	// IDs and dates are left alone
+	phonePattern = regexp.MustCompile(`(?:\+82-?|\b0)\d{1,2}-?\d{3,4}-?\d{4}\b`)
+	phoneKeys    = map[string]bool{"to": true, "from": true, "toList": true, "recipientNumber": true, "senderNumber": true, "phoneNumber": true}
+	textKeys     = map[string]bool{"text": true, "subject": true, "content": true}
+	secretKeys   = map[string]bool{"apiSecret": true, "ApiSecret": true, "apisecret": true, "signature": true, "authorization": true, "Authorization": true}
+)
+
+// Redactor masks phone numbers and message text unless told to show them.
+// Secrets are always redacted.
+type Redactor struct {
+	ShowPhoneNumbers bool
+	ShowText         bool
+	// KeysOnly masks phone numbers under phone keys only and leaves other
+	// strings as they are, for output that must keep every ID intact.
+	KeysOnly bool
+}
+
+// Phone masks all but the last four digits of a phone number. The result is
+// deterministic, so masked values can still be compared.
+func Phone(s string) string {
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	out := []rune(s)
+	keep := 4
+	for i := len(out) - 1; i >= 0; i-- {
+		if out[i] < '0' || out[i] > '9' {
+			continue
+		}
+		if keep > 0 && digits > 4 {
+			keep--
+			continue
+		}
+		out[i] = '*'
+	}
+	return string(out)
+}
+
+// String masks Korean phone numbers embedded in free text, such as error
+// messages. IDs and timestamps pass through unchanged.
+func (r Redactor) String(s string) string {
+	if r.ShowPhoneNumbers {
+		return s
+	}
+	return phonePattern.ReplaceAllStringFunc(s, Phone)
+}
+
+// JSON returns a redacted copy of a JSON document. Input that is not JSON
+// is treated as free text.
+func (r Redactor) JSON(b []byte) []byte {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return []byte(r.String(string(b)))
+	}
+	out, err := json.Marshal(r.Value("", v))
+	if err != nil {
+		return []byte(Placeholder)
+	}
+	return out
+}
+
+// Value redacts a decoded JSON value found under key, in place where
+// possible.
+func (r Redactor) Value(key string, v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			x[k] = r.Value(k, e)
+		}
+		return x
+	case []any:
+		for i, e := range x {
+			x[i] = r.Value(key, e)
+		}
+		return x
+	case string:
+		switch {
+		case secretKeys[key]:
+			return Placeholder
+		case textKeys[key] && !r.ShowText:
+			return Placeholder
+		case phoneKeys[key] && !r.ShowPhoneNumbers:
+			return Phone(x)
+		case r.KeysOnly:
+			return x
+		}
+		return r.String(x)
+	}
+	return v
+}
+
+// Headers returns h flattened with credentials removed. The Authorization
+// scheme and public API key are kept for troubleshooting.
+func Headers(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, vs := range h {
+		v := strings.Join(vs, ",")
+		if secretKeys[k] {
+			v = Placeholder
+			if scheme, rest, ok := strings.Cut(vs[0], " "); ok {
+				if i := strings.Index(rest, "apiKey="); i >= 0 {
+					key, _, _ := strings.Cut(rest[i:], ",")
+					v = scheme + " " + key + ", " + Placeholder
+				}
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package redact
+
+import "testing"
+
+func TestRedactor_JSON(t *testing.T) {
+	in := `{"groupId":"G4V20231019123456ABCDEFG","dateCreated":"2023-10-19T12:34:56.789Z","to":"01012345678","toList":["010-1111-2222"],` +
+		`"errorMessage":"bad number 010-9876-5432 or +82-10-1234-5678","text":"hi","apiSecret":"s"}`
+	got := string(Redactor{}.JSON([]byte(in)))
+	want := `{"apiSecret":"[REDACTED]","dateCreated":"2023-10-19T12:34:56.789Z","errorMessage":"bad number ***-****-5432 or +**-**-****-5678",` +
+		`"groupId":"G4V20231019123456ABCDEFG","text":"[REDACTED]","to":"*******5678","toList":["***-****-2222"]}`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestRedactor_StringKeepsIDs(t *testing.T) {
+	for _, s := range []string{
+		"G4V20231019123456ABCDEFG",
+		"M4V20231019123456ABCDEFGHIJKLMNO",
+		"2023-10-19 12:34:56",
+		"20231019123456",
+		"order 1234567890",
+	} {
+		if got := (Redactor{}).String(s); got != s {
+			t.Errorf("String(%q) = %q", s, got)
+		}
+	}
+}
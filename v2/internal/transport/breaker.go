@@ -0,0 +1,172 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/solapierr"
+)
+
+// CircuitState is the state of a Breaker.
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Breaker opens after Threshold consecutive failures (5xx responses, timeouts
+// and other transport errors), rejects requests with solapierr.ErrCircuitOpen
+// for Cooldown, then lets up to Probes requests through. The circuit closes
+// once that many probes succeed and reopens on the first failing probe.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	probes    int
+	onChange  func(from, to CircuitState)
+	now       func() time.Time
+
+	mu        sync.Mutex
+	state     CircuitState
+	gen       uint64
+	failures  int
+	openedAt  time.Time
+	inflight  int
+	successes int
+}
+
+// NewBreaker returns a closed breaker. onChange, if not nil, is called
+// synchronously on every state transition.
+func NewBreaker(threshold int, cooldown time.Duration, probes int, onChange func(from, to CircuitState)) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	if probes < 1 {
+		probes = 1
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown, probes: probes, onChange: onChange, now: time.Now}
+}
+
+// State returns the current state, accounting for an elapsed cooldown.
+func (b *Breaker) State() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may proceed. The returned generation must
+// be passed to record so results from before a transition are ignored.
+func (b *Breaker) allow() (uint64, error) {
+	if b == nil {
+		return 0, nil
+	}
+	b.mu.Lock()
+	from := b.state
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.transition(CircuitHalfOpen)
+	}
+	var err error
+	switch b.state {
+	case CircuitOpen:
+		err = solapierr.ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.inflight >= b.probes {
+			err = solapierr.ErrCircuitOpen
+		} else {
+			b.inflight++
+		}
+	}
+	gen, to := b.gen, b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+	return gen, err
+}
+
+// record reports the outcome of a request admitted by allow.
+func (b *Breaker) record(gen uint64, failure bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	from := b.state
+	if gen == b.gen {
+		switch b.state {
+		case CircuitClosed:
+			if !failure {
+				b.failures = 0
+			} else if b.failures++; b.failures >= b.threshold {
+				b.transition(CircuitOpen)
+			}
+		case CircuitHalfOpen:
+			b.inflight--
+			if failure {
+				b.transition(CircuitOpen)
+			} else if b.successes++; b.successes >= b.probes {
+				b.transition(CircuitClosed)
+			}
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// abandon releases a request admitted by allow without reporting an outcome,
+// e.g. when the caller canceled it.
+func (b *Breaker) abandon(gen uint64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen == b.gen && b.state == CircuitHalfOpen {
+		b.inflight--
+	}
+}
+
+// transition moves to state and resets counters. Callers must hold b.mu.
+func (b *Breaker) transition(state CircuitState) {
+	b.state = state
+	b.gen++
+	b.failures, b.inflight, b.successes = 0, 0, 0
+	if state == CircuitOpen {
+		b.openedAt = b.now()
+	}
+}
+
+func (b *Breaker) notify(from, to CircuitState) {
+	if from != to && b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
+
+// recordErr reports a transport error. Cancellation by the caller says
+// nothing about the server and is not counted.
+func (b *Breaker) recordErr(gen uint64, err error) {
+	if errors.Is(err, context.Canceled) {
+		b.abandon(gen)
+		return
+	}
+	b.record(gen, true)
+}
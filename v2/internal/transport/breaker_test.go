@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/solapierr"
+)
+
+func TestBreaker_Transitions(t *testing.T) {
+	now := time.Unix(0, 0)
+	var changes []string
+	b := NewBreaker(2, time.Second, 1, func(from, to CircuitState) {
+		changes = append(changes, from.String()+"->"+to.String())
+	})
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		gen, err := b.allow()
+		if err != nil {
+			t.Fatalf("allow %d: %v", i, err)
+		}
+		b.record(gen, true)
+	}
+	if _, err := b.allow(); !errors.Is(err, solapierr.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	probe, err := b.allow()
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if _, err := b.allow(); !errors.Is(err, solapierr.ErrCircuitOpen) {
+		t.Fatalf("second probe should be rejected, got %v", err)
+	}
+	b.record(probe, true)
+	if b.State() != CircuitOpen {
+		t.Fatalf("failed probe should reopen, got %v", b.State())
+	}
+
+	now = now.Add(time.Second)
+	probe, _ = b.allow()
+	b.record(probe, false)
+	if b.State() != CircuitClosed {
+		t.Fatalf("expected closed, got %v", b.State())
+	}
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("changes = %v", changes)
+		}
+	}
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b := NewBreaker(2, time.Second, 1, nil)
+	for _, failure := range []bool{true, false, true} {
+		gen, _ := b.allow()
+		b.record(gen, failure)
+	}
+	if b.State() != CircuitClosed {
+		t.Fatalf("expected closed, got %v", b.State())
+	}
+}
+
+func TestFetchJSON_BreakerStopsRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	opts := &Options{Breaker: NewBreaker(2, time.Minute, 1, nil)}
+	ctx := WithOptions(context.Background(), opts)
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	req := DefaultRequest{URL: srv.URL, Method: http.MethodGet}
+
+	if _, err := FetchJSON[struct{}, struct{}](ctx, params, req, nil); !errors.Is(err, solapierr.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 calls before opening, got %d", calls.Load())
+	}
+	if _, err := FetchJSON[struct{}, struct{}](ctx, params, req, nil); !errors.Is(err, solapierr.ErrCircuitOpen) {
+		t.Fatalf("expected fail fast, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("open circuit must not send requests, got %d calls", calls.Load())
+	}
+}
+
+func TestFetchJSON_BreakerIgnoresClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errorCode":"ValidationError","errorMessage":"bad"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	opts := &Options{Breaker: NewBreaker(1, time.Minute, 1, nil)}
+	ctx := WithOptions(context.Background(), opts)
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	for i := 0; i < 3; i++ {
+		_, err := FetchJSON[struct{}, struct{}](ctx, params, DefaultRequest{URL: srv.URL, Method: http.MethodGet}, nil)
+		var apiErr *ApiError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected ApiError, got %v", err)
+		}
+	}
+	if opts.Breaker.State() != CircuitClosed {
+		t.Fatalf("4xx must not open the circuit")
+	}
+}
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/instrumentation"
+)
+
+// call tracks one API call across its attempts for logging and
+// instrumentation.
+type call struct {
+	ctx   context.Context
+	req   DefaultRequest
+	path  string
+	start time.Time
+	log   *Logging
+	inst  instrumentation.Instrumentation
+	span  instrumentation.Span
+
+	attempts int
+	status   int
+}
+
+// startCall begins tracking req. The returned context carries the span, if
+// instrumentation is configured, and must be used for the HTTP requests.
+func (o *Options) startCall(ctx context.Context, req DefaultRequest) (context.Context, *call) {
+	c := &call{req: req, path: req.URL, start: time.Now(), inst: o.Instrumentation}
+	if o.Log != nil && o.Log.Logger != nil {
+		c.log = o.Log
+	}
+	if u, err := url.Parse(req.URL); err == nil {
+		// the query may carry phone numbers; log the path only
+		c.path = u.Path
+	}
+	if c.inst != nil {
+		attrs := append([]instrumentation.Attribute{instrumentation.String(instrumentation.AttrHTTPMethod, req.Method)}, req.Attrs...)
+		if req.Messages > 0 {
+			attrs = append(attrs, instrumentation.Int(instrumentation.AttrMessageCount, req.Messages))
+		}
+		ctx, c.span = c.inst.StartSpan(ctx, c.operation(), attrs...)
+	}
+	c.ctx = ctx
+	return ctx, c
+}
+
+// operation returns the request's operation name, deriving one from the
+// method and path when the service did not set it.
+func (c *call) operation() string {
+	if c.req.Operation != "" {
+		return c.req.Operation
+	}
+	return strings.ToLower(c.req.Method) + " " + c.path
+}
+
+func (c *call) request(r *http.Request, body []byte) {
+	c.attempts++
+	c.logRequest(r, body)
+}
+
+func (c *call) response(resp *http.Response) {
+	c.status = resp.StatusCode
+	c.logResponse(resp)
+}
+
+func (c *call) retry(reason string) {
+	c.logRetry(reason)
+}
+
+func (c *call) end(err error) {
+	d := time.Since(c.start)
+	c.logEnd(d, err)
+	if c.inst == nil {
+		return
+	}
+	attrs := []instrumentation.Attribute{instrumentation.String(instrumentation.AttrHTTPMethod, c.req.Method)}
+	if c.status != 0 {
+		attrs = append(attrs, instrumentation.Int(instrumentation.AttrHTTPStatus, c.status))
+	}
+	spanAttrs := append(attrs, instrumentation.Int(instrumentation.AttrRetryCount, max(c.attempts-1, 0)))
+	if code := errorCode(err); code != "" {
+		spanAttrs = append(spanAttrs, instrumentation.String(instrumentation.AttrErrorCode, code))
+	}
+	c.span.SetAttributes(spanAttrs...)
+	c.span.End(err)
+	op := c.operation()
+	c.inst.RecordLatency(c.ctx, op, d, attrs...)
+	if err == nil && c.req.Messages > 0 {
+		c.inst.AddMessages(c.ctx, op, c.req.Messages, c.req.Attrs...)
+	}
+}
@@ -0,0 +1,132 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/instrumentation"
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+type recordedSpan struct {
+	op    string
+	attrs map[string]any
+	err   error
+	ended bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...instrumentation.Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) End(err error) { s.err, s.ended = err, true }
+
+type recorder struct {
+	mu        sync.Mutex
+	spans     []*recordedSpan
+	latencies []string
+	messages  int
+}
+
+func (r *recorder) StartSpan(ctx context.Context, op string, attrs ...instrumentation.Attribute) (context.Context, instrumentation.Span) {
+	s := &recordedSpan{op: op, attrs: map[string]any{}}
+	s.SetAttributes(attrs...)
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+	return ctx, s
+}
+
+func (r *recorder) RecordLatency(ctx context.Context, op string, d time.Duration, attrs ...instrumentation.Attribute) {
+	r.mu.Lock()
+	r.latencies = append(r.latencies, op)
+	r.mu.Unlock()
+}
+
+func (r *recorder) AddMessages(ctx context.Context, op string, n int, attrs ...instrumentation.Attribute) {
+	r.mu.Lock()
+	r.messages += n
+	r.mu.Unlock()
+}
+
+func TestFetchJSON_Instrumentation(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	rec := &recorder{}
+	ctx := WithOptions(context.Background(), &Options{Instrumentation: rec})
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	req := DefaultRequest{
+		URL:       srv.URL + "/messages/v4/groups/G1/messages",
+		Method:    http.MethodPut,
+		Operation: "groups.add_messages",
+		Attrs:     []instrumentation.Attribute{instrumentation.String(instrumentation.AttrGroupID, "G1")},
+		Messages:  3,
+	}
+	if _, err := FetchJSON[struct{}, struct{}](ctx, params, req, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(rec.spans))
+	}
+	s := rec.spans[0]
+	if s.op != "groups.add_messages" || !s.ended || s.err != nil {
+		t.Fatalf("unexpected span: %+v", s)
+	}
+	want := map[string]any{
+		instrumentation.AttrGroupID:      "G1",
+		instrumentation.AttrMessageCount: 3,
+		instrumentation.AttrHTTPStatus:   200,
+		instrumentation.AttrRetryCount:   1,
+		instrumentation.AttrHTTPMethod:   http.MethodPut,
+	}
+	for k, v := range want {
+		if s.attrs[k] != v {
+			t.Errorf("attr %s = %v, want %v", k, s.attrs[k], v)
+		}
+	}
+	if len(rec.latencies) != 1 || rec.messages != 3 {
+		t.Fatalf("metrics: latencies=%v messages=%d", rec.latencies, rec.messages)
+	}
+}
+
+func TestFetchJSON_InstrumentationError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errorCode":"ValidationError","errorMessage":"bad"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	rec := &recorder{}
+	ctx := WithOptions(context.Background(), &Options{Instrumentation: rec})
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	req := DefaultRequest{URL: srv.URL + "/messages/v4/send-many/detail", Method: http.MethodPost, Messages: 2}
+	if _, err := FetchJSON[struct{}, struct{}](ctx, params, req, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	s := rec.spans[0]
+	if s.op != "post /messages/v4/send-many/detail" {
+		t.Fatalf("derived operation = %q", s.op)
+	}
+	if s.err == nil || s.attrs[instrumentation.AttrErrorCode] != "ValidationError" {
+		t.Fatalf("unexpected span: %+v", s)
+	}
+	if rec.messages != 0 {
+		t.Fatalf("failed calls must not count messages")
+	}
+}
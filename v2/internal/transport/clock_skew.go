@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+// minSkewCorrection is the smallest drift worth correcting. The Date header
+// has one second resolution, so smaller differences are noise.
+const minSkewCorrection = 5 * time.Second
+
+// buildAuthorization signs with the corrected clock when one is configured.
+func buildAuthorization(ctx context.Context, params auth.CredentialsProvider, clock *auth.Clock) (string, error) {
+	if clock == nil {
+		return auth.BuildAuthorizationHeaderFrom(ctx, params)
+	}
+	p, err := params.Credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	return auth.BuildAuthorizationHeaderWith(p, clock.Now(), "")
+}
+
+// skewErrorCodes are the error codes of requests rejected for their
+// signature or its date, the rejections a drifting clock causes.
+var skewErrorCodes = map[string]bool{
+	"SignatureDoesNotMatch": true,
+	"InvalidSignature":      true,
+	"InvalidDate":           true,
+	"ExpiredDate":           true,
+}
+
+// correctSkew inspects a response rejected with code and, when the rejection
+// is about the signature and the Date header shows the signing clock
+// drifted, updates clock and reports that a retry is worthwhile. Other
+// rejections leave the clock alone, however far off the server's date is.
+func correctSkew(clock *auth.Clock, resp *http.Response, code string) bool {
+	if clock == nil || !skewErrorCodes[code] {
+		return false
+	}
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return false
+	}
+	measured := time.Until(serverTime)
+	drift := measured - clock.Offset()
+	if drift < minSkewCorrection && drift > -minSkewCorrection {
+		return false
+	}
+	clock.SetOffset(measured.Round(time.Second))
+	return true
+}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+var authDateRe = regexp.MustCompile(`date=([^,]+)`)
+
+// skewedServer rejects signatures whose date is more than a minute away from
+// its own clock, which runs ahead of the local one by skew.
+func skewedServer(t *testing.T, skew time.Duration, attempts *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*attempts++
+		serverNow := time.Now().Add(skew)
+		w.Header().Set("Date", serverNow.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		m := authDateRe.FindStringSubmatch(r.Header.Get("Authorization"))
+		signed, err := time.Parse(time.RFC3339, m[1])
+		if err != nil || serverNow.Sub(signed).Abs() > time.Minute {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]string{"errorCode": "InvalidDate", "errorMessage": "skewed"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(okResponse{Message: "ok"})
+	}))
+}
+
+func TestFetchJSON_CorrectsClockSkewAndRetriesOnce(t *testing.T) {
+	t.Parallel()
+	attempts := 0
+	srv := skewedServer(t, 10*time.Minute, &attempts)
+	t.Cleanup(srv.Close)
+
+	clock := &auth.Clock{}
+	ctx := WithOptions(context.Background(), &Options{Clock: clock})
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	req := DefaultRequest{URL: srv.URL, Method: http.MethodGet}
+	res, err := FetchJSON[struct{}, okResponse](ctx, params, req, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Message != "ok" || attempts != 2 {
+		t.Fatalf("message=%q attempts=%d", res.Message, attempts)
+	}
+	if d := clock.Offset() - 10*time.Minute; d.Abs() > 2*time.Second {
+		t.Fatalf("unexpected offset: %v", clock.Offset())
+	}
+
+	// subsequent requests are signed with the corrected clock right away
+	if _, err := FetchJSON[struct{}, okResponse](ctx, params, req, nil); err != nil || attempts != 3 {
+		t.Fatalf("err=%v attempts=%d", err, attempts)
+	}
+}
+
+func TestFetchJSON_NoClockLeavesRejectionAlone(t *testing.T) {
+	t.Parallel()
+	attempts := 0
+	srv := skewedServer(t, 10*time.Minute, &attempts)
+	t.Cleanup(srv.Close)
+
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	req := DefaultRequest{URL: srv.URL, Method: http.MethodGet}
+	if _, err := FetchJSON[struct{}, okResponse](context.Background(), params, req, nil); err == nil {
+		t.Fatalf("expected error without clock correction")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a single attempt, got %d", attempts)
+	}
+}
+
+func TestFetchJSON_UnrelatedRejectionKeepsClock(t *testing.T) {
+	t.Parallel()
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Date", time.Now().Add(10*time.Minute).UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]string{"errorCode": "BlockedSenderNumber", "errorMessage": "blocked"})
+	}))
+	t.Cleanup(srv.Close)
+
+	clock := &auth.Clock{}
+	ctx := WithOptions(context.Background(), &Options{Clock: clock})
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	if _, err := FetchJSON[struct{}, okResponse](ctx, params, DefaultRequest{URL: srv.URL, Method: http.MethodGet}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 || clock.Offset() != 0 {
+		t.Fatalf("attempts=%d offset=%v", attempts, clock.Offset())
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/solapierr"
 )
 
 func TestFetchJSON_4xxReturnsApiError(t *testing.T) {
@@ -52,3 +53,24 @@ func TestFetchJSON_5xxReturnsDefaultError(t *testing.T) {
 		t.Fatalf("expected DefaultError, got %v", err)
 	}
 }
+
+func TestFetchJSON_404MatchesNotFoundSentinel(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"errorCode": "ResourceNotFound", "errorMessage": "no group"})
+	}))
+	t.Cleanup(srv.Close)
+
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	req := DefaultRequest{URL: srv.URL, Method: http.MethodGet}
+	_, err := FetchJSON[struct{}, struct{}](context.Background(), params, req, nil)
+	if !errors.Is(err, solapierr.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	var apiErr *solapierr.ApiError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *solapierr.ApiError, got %T", err)
+	}
+}
@@ -1,35 +1,10 @@
 package transport
 
-import (
-	"fmt"
-)
-
-type ApiError struct {
-	ErrorCode    string
-	ErrorMessage string
-	HTTPStatus   int
-	URL          string
-}
-
-func (e *ApiError) Error() string {
-	if e == nil {
-		return "<nil>"
-	}
-	return fmt.Sprintf("api error %s (%d) %s: %s", e.ErrorCode, e.HTTPStatus, e.URL, e.ErrorMessage)
-}
+import "github.com/solapi/solapi-go/v2/solapierr"
 
-type DefaultError struct {
-	ErrorCode    string
-	ErrorMessage string
-	Context      map[string]any
-}
-
-func (e *DefaultError) Error() string {
-	if e == nil {
-		return "<nil>"
-	}
-	if e.ErrorCode == "" {
-		return e.ErrorMessage
-	}
-	return fmt.Sprintf("%s: %s", e.ErrorCode, e.ErrorMessage)
-}
+// ApiError and DefaultError live in the public solapierr package so callers
+// can match them with errors.As; the aliases keep transport code unchanged.
+type (
+	ApiError     = solapierr.ApiError
+	DefaultError = solapierr.DefaultError
+)
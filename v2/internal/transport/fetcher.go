@@ -9,53 +9,79 @@ import (
 	"net/http"
 
 	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/solapierr"
 )
 
-var errRetryable = errors.New("retryable")
+var (
+	errRetryable = errors.New("retryable")
+	errSkewRetry = errors.New("clock skew corrected")
+)
 
 // FetchJSON performs an HTTP request with Authorization header, retries on 503,
 // maps 4xx to ApiError and 5xx to DefaultError, and decodes JSON into TRes.
-func FetchJSON[TReq any, TRes any](ctx context.Context, params auth.AuthenticationParameter, req DefaultRequest, body *TReq) (TRes, error) {
+func FetchJSON[TReq any, TRes any](ctx context.Context, params auth.CredentialsProvider, req DefaultRequest, body *TReq) (TRes, error) {
 	return FetchJSONWithClient[TReq, TRes](ctx, httpClientFromContext(ctx), params, req, body)
 }
 
 // FetchJSONWithClient is like FetchJSON but uses the provided *http.Client.
 // Library users can configure timeouts, transports, and middlewares via this client.
-func FetchJSONWithClient[TReq any, TRes any](ctx context.Context, httpClient *http.Client, params auth.AuthenticationParameter, req DefaultRequest, body *TReq) (TRes, error) {
+// Credentials are resolved from params on every attempt so rotated secrets
+// take effect without rebuilding the client.
+func FetchJSONWithClient[TReq any, TRes any](ctx context.Context, httpClient *http.Client, params auth.CredentialsProvider, req DefaultRequest, body *TReq) (_ TRes, err error) {
 	var zero TRes
 	if req.URL == "" || req.Method == "" {
 		return zero, errors.New("invalid request")
 	}
 
-	authz, err := auth.BuildAuthorizationHeader(params)
-	if err != nil {
-		return zero, err
-	}
-
+	opts := optionsFromContext(ctx)
+	ctx, call := opts.startCall(ctx, req)
+	defer func() { call.end(err) }()
+	limits := opts.limitsFor(req)
+	skewRetried := false
 	const maxRetry = 3
 	for attempt := 0; attempt <= maxRetry; attempt++ {
-		var buf *bytes.Reader
+		var payload []byte
 		if body != nil {
 			b, e := json.Marshal(body)
 			if e != nil {
 				return zero, e
 			}
-			buf = bytes.NewReader(b)
-		} else {
-			buf = bytes.NewReader(nil)
+			payload = b
 		}
+		buf := bytes.NewReader(payload)
 
+		// wait for limits before signing so the signature date stays fresh
+		release, err := acquireLimits(ctx, limits)
+		if err != nil {
+			return zero, err
+		}
+		authz, err := buildAuthorization(ctx, params, opts.Clock)
+		if err != nil {
+			release()
+			return zero, err
+		}
 		httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, buf)
 		if err != nil {
+			release()
 			return zero, err
 		}
 		httpReq.Header.Set("Authorization", authz)
 		httpReq.Header.Set("Content-Type", "application/json")
 
+		gen, err := opts.Breaker.allow()
+		if err != nil {
+			release()
+			return zero, err
+		}
+		call.request(httpReq, payload)
+
 		// Use provided client; caller is responsible for sensible defaults (e.g., timeouts)
 		resp, err := httpClient.Do(httpReq)
 		if err != nil {
-			if attempt < maxRetry {
+			release()
+			opts.Breaker.recordErr(gen, err)
+			if attempt < maxRetry && !req.NoNetworkRetry {
+				call.retry(err.Error())
 				continue
 			}
 			return zero, err
@@ -64,7 +90,13 @@ func FetchJSONWithClient[TReq any, TRes any](ctx context.Context, httpClient *ht
 		var retErr error
 		var result TRes
 		func() {
+			defer release()
 			defer resp.Body.Close()
+			for _, l := range limits {
+				l.observe(resp.StatusCode)
+			}
+			opts.Breaker.record(gen, resp.StatusCode >= 500)
+			call.response(resp)
 			if resp.StatusCode == http.StatusServiceUnavailable {
 				if attempt < maxRetry {
 					_, _ = io.Copy(io.Discard, resp.Body)
@@ -81,7 +113,17 @@ func FetchJSONWithClient[TReq any, TRes any](ctx context.Context, httpClient *ht
 					er.ErrorCode = "ParseError"
 					er.ErrorMessage = decErr.Error()
 				}
-				retErr = &ApiError{ErrorCode: er.ErrorCode, ErrorMessage: er.ErrorMessage, HTTPStatus: resp.StatusCode, URL: req.URL}
+				if !skewRetried && correctSkew(opts.Clock, resp, er.ErrorCode) {
+					_, _ = io.Copy(io.Discard, resp.Body)
+					retErr = errSkewRetry
+					return
+				}
+				apiErr := &ApiError{ErrorCode: er.ErrorCode, ErrorMessage: er.ErrorMessage, HTTPStatus: resp.StatusCode, URL: req.URL}
+				if errors.Is(apiErr, solapierr.ErrInvalidCredentials) {
+					// let caching providers pick up rotated credentials next time
+					auth.Invalidate(params)
+				}
+				retErr = apiErr
 				return
 			}
 			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -105,6 +147,14 @@ func FetchJSONWithClient[TReq any, TRes any](ctx context.Context, httpClient *ht
 			return result, nil
 		}
 		if errors.Is(retErr, errRetryable) {
+			call.retry(http.StatusText(resp.StatusCode))
+			continue
+		}
+		if errors.Is(retErr, errSkewRetry) {
+			// re-sign once with the corrected clock without using up a retry
+			skewRetried = true
+			call.retry("clock skew corrected")
+			attempt--
 			continue
 		}
 		return zero, retErr
@@ -0,0 +1,149 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/solapi/solapi-go/v2/internal/ratelimit"
+)
+
+// EndpointClass groups endpoints that share a limit.
+type EndpointClass string
+
+const (
+	ClassSend   EndpointClass = "send"
+	ClassList   EndpointClass = "list"
+	ClassUpload EndpointClass = "upload"
+)
+
+// classify derives the endpoint class of a request from its method and URL.
+func classify(req DefaultRequest) EndpointClass {
+	switch {
+	case strings.Contains(req.URL, "/storage/"):
+		return ClassUpload
+	case req.Method == http.MethodGet:
+		return ClassList
+	default:
+		return ClassSend
+	}
+}
+
+const (
+	// adaptiveFloor is the smallest fraction of the configured rate an
+	// adaptive limit slows down to.
+	adaptiveFloor = 1.0 / 16
+	// adaptiveRecovery is the factor applied to the rate after each success.
+	adaptiveRecovery = 1.05
+)
+
+// Limit combines a token bucket and a cap on in-flight requests. Either part
+// may be disabled with a zero value. A nil *Limit imposes nothing.
+type Limit struct {
+	limiter  *ratelimit.Limiter
+	inflight chan struct{}
+	baseRate float64
+	adaptive bool
+
+	mu sync.Mutex
+}
+
+// NewLimit returns a limit of rps requests per second with the given burst
+// and at most maxInFlight concurrent requests. When adaptive is set, the rate
+// is halved on 429/503 responses and recovers gradually on success.
+func NewLimit(rps float64, burst, maxInFlight int, adaptive bool) *Limit {
+	l := &Limit{baseRate: rps, adaptive: adaptive && rps > 0}
+	if rps > 0 {
+		l.limiter = ratelimit.New(rps, burst)
+	}
+	if maxInFlight > 0 {
+		l.inflight = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// acquire waits for a rate token and an in-flight slot, honoring ctx.
+func (l *Limit) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if err := l.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if l.inflight == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.inflight <- struct{}{}:
+		return func() { <-l.inflight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// observe adapts the rate to the response status.
+func (l *Limit) observe(status int) {
+	if l == nil || !l.adaptive {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rate := l.limiter.Rate()
+	switch {
+	case status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+		rate /= 2
+		if floor := l.baseRate * adaptiveFloor; rate < floor {
+			rate = floor
+		}
+	case status < 400 && rate < l.baseRate:
+		rate *= adaptiveRecovery
+		if rate > l.baseRate {
+			rate = l.baseRate
+		}
+	default:
+		return
+	}
+	l.limiter.SetRate(rate)
+}
+
+// Rate returns the current rate, which may be below the configured one while
+// an adaptive limit is slowed down.
+func (l *Limit) Rate() float64 {
+	if l == nil || l.limiter == nil {
+		return 0
+	}
+	return l.limiter.Rate()
+}
+
+// limitsFor returns the limits that apply to req: the client-wide one and the
+// one for its endpoint class.
+func (o *Options) limitsFor(req DefaultRequest) []*Limit {
+	var out []*Limit
+	if o.Limit != nil {
+		out = append(out, o.Limit)
+	}
+	if l := o.EndpointLimits[classify(req)]; l != nil {
+		out = append(out, l)
+	}
+	return out
+}
+
+// acquireLimits acquires every limit in order and returns a release for all.
+func acquireLimits(ctx context.Context, limits []*Limit) (func(), error) {
+	releases := make([]func(), 0, len(limits))
+	releaseAll := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	for _, l := range limits {
+		r, err := l.acquire(ctx)
+		if err != nil {
+			releaseAll()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	return releaseAll, nil
+}
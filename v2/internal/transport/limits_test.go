@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestFetchJSON_MaxInFlight(t *testing.T) {
+	t.Parallel()
+	var cur, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := cur.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		cur.Add(-1)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := WithOptions(context.Background(), &Options{Limit: NewLimit(0, 0, 2, false)})
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = FetchJSON[struct{}, struct{}](ctx, params, DefaultRequest{URL: srv.URL, Method: http.MethodGet}, nil)
+		}()
+	}
+	wg.Wait()
+	if peak.Load() > 2 {
+		t.Fatalf("in-flight exceeded limit: %d", peak.Load())
+	}
+}
+
+func TestFetchJSON_EndpointLimitHonorsDeadline(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	opts := &Options{EndpointLimits: map[EndpointClass]*Limit{ClassSend: NewLimit(0.5, 1, 0, false)}}
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	send := DefaultRequest{URL: srv.URL + "/messages/v4/send-many/detail", Method: http.MethodPost}
+	if _, err := FetchJSON[struct{}, struct{}](WithOptions(context.Background(), opts), params, send, nil); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(WithOptions(context.Background(), opts), 50*time.Millisecond)
+	defer cancel()
+	if _, err := FetchJSON[struct{}, struct{}](ctx, params, send, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	// list endpoints are not affected by the send limit
+	list := DefaultRequest{URL: srv.URL + "/messages/v4/list", Method: http.MethodGet}
+	if _, err := FetchJSON[struct{}, struct{}](ctx, params, list, nil); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+}
+
+func TestLimit_AdaptiveSlowdownAndRecovery(t *testing.T) {
+	l := NewLimit(100, 1, 0, true)
+	l.observe(http.StatusTooManyRequests)
+	l.observe(http.StatusServiceUnavailable)
+	if r := l.Rate(); r != 25 {
+		t.Fatalf("expected rate 25 after two slowdowns, got %v", r)
+	}
+	for i := 0; i < 100; i++ {
+		l.observe(http.StatusOK)
+	}
+	if r := l.Rate(); r != 100 {
+		t.Fatalf("expected full recovery, got %v", r)
+	}
+}
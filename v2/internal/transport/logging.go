@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/redact"
+)
+
+// Logging configures request logging. Authorization headers and API secrets
+// are always redacted; phone numbers and message text are masked unless
+// explicitly shown.
+type Logging struct {
+	Logger *slog.Logger
+	// LogBodies adds request and response bodies at debug level.
+	LogBodies bool
+	// ShowPhoneNumbers disables masking of phone numbers.
+	ShowPhoneNumbers bool
+	// ShowText disables masking of message text and subjects.
+	ShowText bool
+}
+
+func (l *Logging) redactor() redact.Redactor {
+	return redact.Redactor{ShowPhoneNumbers: l.ShowPhoneNumbers, ShowText: l.ShowText}
+}
+
+// logRequest logs an outgoing attempt at debug level.
+func (c *call) logRequest(r *http.Request, body []byte) {
+	if c.log == nil || !c.log.Logger.Enabled(c.ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", c.req.Method),
+		slog.String("path", c.path),
+		slog.Int("attempt", c.attempts),
+	}
+	if c.log.LogBodies {
+		attrs = append(attrs, slog.Any("headers", redact.Headers(r.Header)))
+		if len(body) > 0 {
+			attrs = append(attrs, slog.String("body", string(c.log.redactor().JSON(body))))
+		}
+	}
+	c.log.Logger.LogAttrs(c.ctx, slog.LevelDebug, "solapi request", attrs...)
+}
+
+// logResponse logs the body of resp at debug level when bodies are logged,
+// leaving resp.Body readable.
+func (c *call) logResponse(resp *http.Response) {
+	if c.log == nil || !c.log.LogBodies || !c.log.Logger.Enabled(c.ctx, slog.LevelDebug) {
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+	if len(body) == 0 {
+		return
+	}
+	c.log.Logger.LogAttrs(c.ctx, slog.LevelDebug, "solapi response",
+		slog.String("method", c.req.Method),
+		slog.String("path", c.path),
+		slog.Int("status", resp.StatusCode),
+		slog.String("body", string(c.log.redactor().JSON(body))),
+	)
+}
+
+// logRetry logs why an attempt is repeated.
+func (c *call) logRetry(reason string) {
+	if c.log == nil {
+		return
+	}
+	c.log.Logger.LogAttrs(c.ctx, slog.LevelDebug, "solapi retry",
+		slog.String("method", c.req.Method),
+		slog.String("path", c.path),
+		slog.Int("attempt", c.attempts),
+		slog.String("reason", c.log.redactor().String(reason)),
+	)
+}
+
+// logEnd logs the outcome of the call: info on success, warn on failure.
+func (c *call) logEnd(d time.Duration, err error) {
+	if c.log == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", c.req.Method),
+		slog.String("path", c.path),
+		slog.Int("status", c.status),
+		slog.Duration("duration", d),
+		slog.Int("attempts", c.attempts),
+	}
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+		if code := errorCode(err); code != "" {
+			attrs = append(attrs, slog.String("error_code", code))
+		}
+		attrs = append(attrs, slog.String("error", c.log.redactor().String(err.Error())))
+	}
+	c.log.Logger.LogAttrs(c.ctx, level, "solapi call", attrs...)
+}
+
+// errorCode extracts the API error code from err, if any.
+func errorCode(err error) string {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode
+	}
+	var defErr *DefaultError
+	if errors.As(err, &defErr) {
+		return defErr.ErrorCode
+	}
+	return ""
+}
+
+// errReader replays a read error after the buffered body, or io.EOF.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
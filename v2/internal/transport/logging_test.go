@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestFetchJSON_LogsRedacted(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errorCode":"InvalidRecipient","errorMessage":"bad number 01012345678"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	opts := &Options{Log: &Logging{Logger: logger, LogBodies: true}}
+	ctx := WithOptions(context.Background(), opts)
+	params := auth.AuthenticationParameter{ApiKey: "public-key", ApiSecret: "top-secret"}
+	type msg struct {
+		To   string `json:"to"`
+		Text string `json:"text"`
+	}
+	body := struct {
+		Messages []msg `json:"messages"`
+	}{Messages: []msg{{To: "01098765432", Text: "hello there"}}}
+
+	_, err := FetchJSON[struct {
+		Messages []msg `json:"messages"`
+	}, struct{}](ctx, params, DefaultRequest{URL: srv.URL + "/messages/v4/send-many/detail?to=01098765432", Method: http.MethodPost}, &body)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	logs := out.String()
+	for _, leak := range []string{"top-secret", "signature=", "01098765432", "01012345678", "hello there"} {
+		if strings.Contains(logs, leak) {
+			t.Errorf("log leaks %q:\n%s", leak, logs)
+		}
+	}
+	for _, want := range []string{"public-key", "*******5432", "level=WARN", "error_code=InvalidRecipient", "attempts=2", "status=400", "path=/messages/v4/send-many/detail", "solapi retry"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("log missing %q:\n%s", want, logs)
+		}
+	}
+}
+
+func TestLogging_ShowOptions(t *testing.T) {
+	l := &Logging{ShowPhoneNumbers: true, ShowText: true}
+	got := string(l.redactor().JSON([]byte(`{"to":"01012345678","text":"hi","apiSecret":"s"}`)))
+	if !strings.Contains(got, "01012345678") || !strings.Contains(got, `"hi"`) || strings.Contains(got, `"s"`) {
+		t.Fatalf("unexpected redaction: %s", got)
+	}
+}
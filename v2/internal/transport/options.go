@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/solapi/solapi-go/v2/instrumentation"
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+// Options carries per-client transport settings shared by all services of a
+// client. A nil *Options means defaults.
+type Options struct {
+	// Clock, when set, is used to sign requests and is corrected when the
+	// API rejects a signature because of clock skew.
+	Clock *auth.Clock
+
+	// Limit applies to every request of the client.
+	Limit *Limit
+	// EndpointLimits apply per endpoint class, in addition to Limit.
+	EndpointLimits map[EndpointClass]*Limit
+
+	// Breaker, when set, fails requests fast while the API keeps failing.
+	Breaker *Breaker
+
+	// Log, when set, logs every call with secrets and PII redacted.
+	Log *Logging
+
+	// Instrumentation, when set, receives a span and metrics for every call.
+	Instrumentation instrumentation.Instrumentation
+}
+
+const optionsKey ctxKey = 2
+
+// WithOptions stores *Options in context for transport to use.
+func WithOptions(ctx context.Context, o *Options) context.Context {
+	if o == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, optionsKey, o)
+}
+
+// optionsFromContext retrieves *Options from context or returns empty options.
+func optionsFromContext(ctx context.Context) *Options {
+	if v, ok := ctx.Value(optionsKey).(*Options); ok && v != nil {
+		return v
+	}
+	return &Options{}
+}
+
+// optionsTransport attaches Options to the http.Client a service is built
+// with; it only delegates to base.
+type optionsTransport struct {
+	base http.RoundTripper
+	opts *Options
+}
+
+func (t *optionsTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if t.base == nil {
+		return http.DefaultTransport.RoundTrip(r)
+	}
+	return t.base.RoundTrip(r)
+}
+
+// AttachOptions returns a copy of hc carrying o. Services built with the
+// returned client apply o through WithClient.
+func AttachOptions(hc *http.Client, o *Options) *http.Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	nc := *hc
+	if t, ok := hc.Transport.(*optionsTransport); ok {
+		nc.Transport = &optionsTransport{base: t.base, opts: o}
+	} else {
+		nc.Transport = &optionsTransport{base: hc.Transport, opts: o}
+	}
+	return &nc
+}
+
+// WithClient stores hc and the Options attached to it in ctx.
+func WithClient(ctx context.Context, hc *http.Client) context.Context {
+	if hc != nil {
+		if t, ok := hc.Transport.(*optionsTransport); ok {
+			ctx = WithOptions(ctx, t.opts)
+		}
+	}
+	return WithHTTPClient(ctx, hc)
+}
@@ -0,0 +1,31 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAttachOptions(t *testing.T) {
+	o := &Options{}
+	base := &http.Client{}
+	hc := AttachOptions(base, o)
+	if base.Transport != nil {
+		t.Fatal("AttachOptions modified the caller's client")
+	}
+	ctx := WithClient(context.Background(), hc)
+	if optionsFromContext(ctx) != o || httpClientFromContext(ctx) != hc {
+		t.Fatal("options not carried through the context")
+	}
+
+	// reattaching replaces the options instead of nesting wrappers
+	o2 := &Options{}
+	hc2 := AttachOptions(hc, o2)
+	if hc2.Transport.(*optionsTransport).base != nil || optionsFromContext(WithClient(context.Background(), hc2)) != o2 {
+		t.Fatal("reattached options not applied")
+	}
+
+	if optionsFromContext(WithClient(context.Background(), base)) == o {
+		t.Fatal("plain client carries options")
+	}
+}
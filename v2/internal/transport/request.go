@@ -1,7 +1,19 @@
 package transport
 
+import "github.com/solapi/solapi-go/v2/instrumentation"
+
 // DefaultRequest is a minimal HTTP request descriptor used by transport layer.
 type DefaultRequest struct {
 	URL    string
 	Method string
+
+	// Operation names the call for instrumentation, e.g. "groups.send".
+	Operation string
+	// Attrs are added to the call's span.
+	Attrs []instrumentation.Attribute
+	// Messages is the number of messages the call submits, counted on success.
+	Messages int
+	// NoNetworkRetry disables retries after network errors, whose outcome is
+	// unknown. Callers set it when they check for an earlier attempt first.
+	NoNetworkRetry bool
 }
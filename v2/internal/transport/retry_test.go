@@ -3,6 +3,7 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -42,3 +43,30 @@ func TestFetchJSON_RetryOn503ThenSuccess(t *testing.T) {
 		t.Fatalf("expected retry, attempts=%d", attempts)
 	}
 }
+
+type failingTransport struct{ calls int }
+
+func (f *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	f.calls++
+	return nil, errors.New("connection reset")
+}
+
+func TestFetchJSON_NoNetworkRetry(t *testing.T) {
+	t.Parallel()
+
+	params := auth.AuthenticationParameter{ApiKey: "key", ApiSecret: "secret"}
+	for _, tc := range []struct {
+		noRetry bool
+		want    int
+	}{{false, 4}, {true, 1}} {
+		ft := &failingTransport{}
+		req := DefaultRequest{URL: "http://solapi.invalid/x", Method: http.MethodPost, NoNetworkRetry: tc.noRetry}
+		_, err := FetchJSONWithClient[struct{}, retryOK](context.Background(), &http.Client{Transport: ft}, params, req, &struct{}{})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if ft.calls != tc.want {
+			t.Fatalf("NoNetworkRetry=%v: calls=%d, want %d", tc.noRetry, ft.calls, tc.want)
+		}
+	}
+}
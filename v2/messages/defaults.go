@@ -0,0 +1,30 @@
+package messages
+
+// Defaults are applied to sends that leave the fields empty, e.g. from a
+// config profile.
+type Defaults struct {
+	AppId string
+	// From is the sender number for messages without one.
+	From string
+}
+
+// SetDefaults replaces the defaults applied by SendManyDetail. It must not be
+// called concurrently with sends.
+func (s *Service) SetDefaults(d Defaults) {
+	s.defaults = d
+}
+
+// Apply returns msgs with From filled in where empty. msgs is not modified.
+func (d Defaults) Apply(msgs []Message) []Message {
+	if d.From == "" {
+		return msgs
+	}
+	out := make([]Message, len(msgs))
+	for i, m := range msgs {
+		if m.From == "" {
+			m.From = d.From
+		}
+		out[i] = m
+	}
+	return out
+}
@@ -1,5 +1,10 @@
 package messages
 
+import (
+	"fmt"
+	"strings"
+)
+
 type MessageNotReceivedError struct {
 	FailedMessageList []FailedMessage
 	TotalCount        int
@@ -8,3 +13,120 @@ type MessageNotReceivedError struct {
 func (e *MessageNotReceivedError) Error() string {
 	return "all messages failed to be registered"
 }
+
+// PartialFailure ties a registration failure back to the request message.
+type PartialFailure struct {
+	// Index is the position of the message in SendRequest.Messages.
+	Index int
+	// Message is the original message narrowed to the failed recipient.
+	Message Message
+	Failed  FailedMessage
+}
+
+// PartialSendError is returned by SendManyDetail when SendRequest.FailOnPartial
+// is set and some, but not all, messages failed to be registered. The response
+// is returned alongside the error.
+type PartialSendError struct {
+	Response DetailGroupMessageResponse
+	Failures []PartialFailure
+	// Unmatched holds failures that could not be mapped to a request message.
+	Unmatched []FailedMessage
+}
+
+func (e *PartialSendError) Error() string {
+	return fmt.Sprintf("%d of %d messages failed to be registered", len(e.Failures)+len(e.Unmatched), e.Response.GroupInfo.Count.Total)
+}
+
+// retryableStatusCodes are the registration failures known to be
+// temporary.
+var retryableStatusCodes = map[string]bool{
+	"3040": true, // carrier timed out
+	"3059": true, // carrier busy
+}
+
+// IsRetryableFailure is the default retry policy for registration failures.
+// Only status codes known to be temporary are retried; empty and unknown
+// codes, like the 1000 range describing invalid input, are treated as
+// permanent. Pass a custom policy to ResendFailures for other codes.
+func IsRetryableFailure(f FailedMessage) bool {
+	return retryableStatusCodes[f.StatusCode]
+}
+
+// RetryableFailures returns failures accepted by retryable, or by
+// IsRetryableFailure when retryable is nil.
+func (e *PartialSendError) RetryableFailures(retryable func(FailedMessage) bool) []PartialFailure {
+	if retryable == nil {
+		retryable = IsRetryableFailure
+	}
+	var out []PartialFailure
+	for _, f := range e.Failures {
+		if retryable(f.Failed) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// RetryMessages returns the messages of the retryable failures, ready to be
+// passed to Send.
+func (e *PartialSendError) RetryMessages(retryable func(FailedMessage) bool) []Message {
+	failures := e.RetryableFailures(retryable)
+	out := make([]Message, 0, len(failures))
+	for _, f := range failures {
+		out = append(out, f.Message)
+	}
+	return out
+}
+
+// mapFailures matches failed messages to request messages by recipient and
+// sender. Each recipient slot is matched at most once, so duplicates map to
+// successive messages in request order.
+func mapFailures(msgs []Message, failed []FailedMessage) ([]PartialFailure, []FailedMessage) {
+	type slot struct {
+		index int
+		to    string
+	}
+	var slots []slot
+	for i, m := range msgs {
+		if len(m.ToList) > 0 {
+			for _, to := range m.ToList {
+				slots = append(slots, slot{i, to})
+			}
+			continue
+		}
+		slots = append(slots, slot{i, m.To})
+	}
+	used := make([]bool, len(slots))
+	var matched []PartialFailure
+	var unmatched []FailedMessage
+	for _, f := range failed {
+		found := false
+		for j, sl := range slots {
+			m := msgs[sl.index]
+			if used[j] || digitsOnly(sl.to) != digitsOnly(f.To) {
+				continue
+			}
+			if f.From != "" && m.From != "" && digitsOnly(f.From) != digitsOnly(m.From) {
+				continue
+			}
+			used[j] = true
+			m.To, m.ToList = sl.to, nil
+			matched = append(matched, PartialFailure{Index: sl.index, Message: m, Failed: f})
+			found = true
+			break
+		}
+		if !found {
+			unmatched = append(unmatched, f)
+		}
+	}
+	return matched, unmatched
+}
+
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
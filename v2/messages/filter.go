@@ -0,0 +1,247 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrRecipientRejected matches a *RecipientRejectedError.
+	ErrRecipientRejected = errors.New("messages: recipient rejected")
+	// ErrNoRecipients is returned when filters removed every recipient of a
+	// send.
+	ErrNoRecipients = errors.New("messages: no recipients left after filtering")
+)
+
+// RecipientAction is what a RecipientFilter decides for one recipient.
+type RecipientAction int
+
+const (
+	// RecipientKeep sends to the recipient unchanged.
+	RecipientKeep RecipientAction = iota
+	// RecipientDrop removes the recipient; a message left without recipients
+	// is removed.
+	RecipientDrop
+	// RecipientRewrite replaces the recipient with RecipientDecision.To.
+	RecipientRewrite
+	// RecipientReject fails the whole send with a *RecipientRejectedError.
+	RecipientReject
+)
+
+func (a RecipientAction) String() string {
+	switch a {
+	case RecipientKeep:
+		return "keep"
+	case RecipientDrop:
+		return "drop"
+	case RecipientRewrite:
+		return "rewrite"
+	case RecipientReject:
+		return "reject"
+	}
+	return "unknown"
+}
+
+// RecipientDecision is the outcome of a RecipientFilter.
+type RecipientDecision struct {
+	Action RecipientAction
+	// To is the replacement recipient for RecipientRewrite.
+	To string
+	// Reason explains the decision in reports and errors.
+	Reason string
+}
+
+// RecipientFilter inspects every recipient before messages are sent or added
+// to a group. Filters run in order; a recipient rewritten by one filter is
+// seen by the next one under its new number. Implementations must be safe
+// for concurrent use.
+type RecipientFilter interface {
+	FilterRecipient(ctx context.Context, m Message, to string) (RecipientDecision, error)
+}
+
+// RecipientFilterFunc adapts a function to a RecipientFilter.
+type RecipientFilterFunc func(ctx context.Context, m Message, to string) (RecipientDecision, error)
+
+func (f RecipientFilterFunc) FilterRecipient(ctx context.Context, m Message, to string) (RecipientDecision, error) {
+	return f(ctx, m, to)
+}
+
+// FilteredRecipient reports a recipient that a filter did not keep as is.
+type FilteredRecipient struct {
+	// Index is the position of the message in the input.
+	Index  int
+	To     string
+	Action RecipientAction
+	// RewrittenTo is set for RecipientRewrite.
+	RewrittenTo string
+	Reason      string
+}
+
+// RecipientRejectedError lists the recipients that caused a send to be
+// rejected. Nothing was sent.
+type RecipientRejectedError struct {
+	Recipients []FilteredRecipient
+}
+
+func (e *RecipientRejectedError) Error() string {
+	if len(e.Recipients) == 0 {
+		return ErrRecipientRejected.Error()
+	}
+	r := e.Recipients[0]
+	msg := fmt.Sprintf("%s: %s (message %d)", ErrRecipientRejected, r.To, r.Index)
+	if r.Reason != "" {
+		msg += ": " + r.Reason
+	}
+	if n := len(e.Recipients) - 1; n > 0 {
+		msg += fmt.Sprintf(" and %d more", n)
+	}
+	return msg
+}
+
+func (e *RecipientRejectedError) Is(target error) bool { return target == ErrRecipientRejected }
+
+// FilterRecipients applies filters to every recipient of msgs, including each
+// entry of ToList, and returns the messages to send together with a report
+// of dropped and rewritten recipients. If any recipient is rejected, it
+// returns a *RecipientRejectedError listing all of them. msgs is not
+// modified.
+func FilterRecipients(ctx context.Context, msgs []Message, filters ...RecipientFilter) ([]Message, []FilteredRecipient, error) {
+	out, _, report, err := filterRecipients(ctx, msgs, filters)
+	return out, report, err
+}
+
+// filterRecipients is FilterRecipients that also returns, for every output
+// message, its index in msgs.
+func filterRecipients(ctx context.Context, msgs []Message, filters []RecipientFilter) ([]Message, []int, []FilteredRecipient, error) {
+	if len(filters) == 0 {
+		return msgs, nil, nil, nil
+	}
+	out := make([]Message, 0, len(msgs))
+	index := make([]int, 0, len(msgs))
+	var report, rejected []FilteredRecipient
+	for i, m := range msgs {
+		recipients := m.ToList
+		if len(recipients) == 0 {
+			recipients = []string{m.To}
+		}
+		kept := make([]string, 0, len(recipients))
+		rewritten := false
+	recipient:
+		for _, orig := range recipients {
+			to := orig
+			for _, f := range filters {
+				d, err := f.FilterRecipient(ctx, m, to)
+				if err != nil {
+					return nil, nil, report, err
+				}
+				switch d.Action {
+				case RecipientDrop:
+					report = append(report, FilteredRecipient{Index: i, To: orig, Action: RecipientDrop, Reason: d.Reason})
+					continue recipient
+				case RecipientReject:
+					rejected = append(rejected, FilteredRecipient{Index: i, To: orig, Action: RecipientReject, Reason: d.Reason})
+					continue recipient
+				case RecipientRewrite:
+					report = append(report, FilteredRecipient{Index: i, To: orig, Action: RecipientRewrite, RewrittenTo: d.To, Reason: d.Reason})
+					to = d.To
+					rewritten = true
+				}
+			}
+			kept = append(kept, to)
+		}
+		if rewritten {
+			// recipients rewritten to the same number are sent to once
+			kept = uniqueNumbers(kept)
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		if len(m.ToList) > 0 {
+			m.ToList = kept
+		} else {
+			m.To = kept[0]
+		}
+		out = append(out, m)
+		index = append(index, i)
+	}
+	if len(rejected) > 0 {
+		return nil, nil, append(report, rejected...), &RecipientRejectedError{Recipients: rejected}
+	}
+	return out, index, report, nil
+}
+
+// uniqueNumbers returns numbers without the entries whose digits repeat an
+// earlier entry.
+func uniqueNumbers(numbers []string) []string {
+	seen := make(map[string]bool, len(numbers))
+	out := numbers[:0]
+	for _, n := range numbers {
+		if d := digitsOnly(n); !seen[d] {
+			seen[d] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// RecipientPolicyMode selects what a RecipientPolicy does with recipients
+// outside its allowlist.
+type RecipientPolicyMode int
+
+const (
+	// PolicyReject fails the send.
+	PolicyReject RecipientPolicyMode = iota
+	// PolicyRewrite sends to RecipientPolicy.RewriteTo instead, once per
+	// message however many of its recipients are rewritten.
+	PolicyRewrite
+	// PolicyDrop removes the recipient and reports it.
+	PolicyDrop
+)
+
+// RecipientPolicy is a RecipientFilter that only lets allowlisted numbers
+// through, e.g. to keep QA environments from texting customers. Numbers are
+// compared by their digits, so "010-1234-5678" matches "01012345678".
+type RecipientPolicy struct {
+	// Allow lists exact numbers.
+	Allow []string
+	// AllowPrefixes lists number prefixes, such as a test range.
+	AllowPrefixes []string
+	Mode          RecipientPolicyMode
+	// RewriteTo is the test number used by PolicyRewrite.
+	RewriteTo string
+}
+
+// Allows reports whether to is on the allowlist.
+func (p *RecipientPolicy) Allows(to string) bool {
+	d := digitsOnly(to)
+	for _, a := range p.Allow {
+		if digitsOnly(a) == d {
+			return true
+		}
+	}
+	for _, prefix := range p.AllowPrefixes {
+		if strings.HasPrefix(d, digitsOnly(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RecipientPolicy) FilterRecipient(ctx context.Context, m Message, to string) (RecipientDecision, error) {
+	if p.Allows(to) {
+		return RecipientDecision{}, nil
+	}
+	const reason = "not in recipient allowlist"
+	switch p.Mode {
+	case PolicyRewrite:
+		if p.RewriteTo == "" {
+			return RecipientDecision{}, errors.New("messages: RecipientPolicy.RewriteTo is required with PolicyRewrite")
+		}
+		return RecipientDecision{Action: RecipientRewrite, To: p.RewriteTo, Reason: reason}, nil
+	case PolicyDrop:
+		return RecipientDecision{Action: RecipientDrop, Reason: reason}, nil
+	}
+	return RecipientDecision{Action: RecipientReject, Reason: reason}, nil
+}
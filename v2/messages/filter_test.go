@@ -0,0 +1,90 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestFilterRecipients_Policy(t *testing.T) {
+	msgs := []Message{
+		{To: "010-1111-2222", Text: "allowed"},
+		{ToList: []string{"01099990000", "01011112222"}, Text: "mixed"},
+		{To: "01033334444", Text: "blocked"},
+	}
+	drop := &RecipientPolicy{Allow: []string{"01011112222"}, Mode: PolicyDrop}
+	out, report, err := FilterRecipients(context.Background(), msgs, drop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || len(out[1].ToList) != 1 || out[1].ToList[0] != "01011112222" {
+		t.Fatalf("unexpected messages: %+v", out)
+	}
+	if len(report) != 2 || report[0].Index != 1 || report[1].Index != 2 || report[1].Action != RecipientDrop {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if msgs[1].ToList[0] != "01099990000" {
+		t.Fatal("input must not be modified")
+	}
+
+	rewrite := &RecipientPolicy{AllowPrefixes: []string{"0101111"}, Mode: PolicyRewrite, RewriteTo: "01000000000"}
+	out, _, err = FilterRecipients(context.Background(), msgs, rewrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[2].To != "01000000000" || out[0].To != "010-1111-2222" {
+		t.Fatalf("unexpected rewrite: %+v", out)
+	}
+	if len(out[1].ToList) != 2 || out[1].ToList[0] != "01000000000" {
+		t.Fatalf("unexpected rewritten list: %+v", out[1])
+	}
+	out, _, err = FilterRecipients(context.Background(), []Message{{ToList: []string{"01099990000", "01088880000", "01011112222"}}}, rewrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out[0].ToList) != 2 || out[0].ToList[0] != "01000000000" || out[0].ToList[1] != "01011112222" {
+		t.Fatalf("rewritten recipients must be sent to once: %+v", out[0].ToList)
+	}
+
+	reject := &RecipientPolicy{Allow: []string{"01011112222"}}
+	_, report, err = FilterRecipients(context.Background(), msgs, reject)
+	var rej *RecipientRejectedError
+	if !errors.As(err, &rej) || !errors.Is(err, ErrRecipientRejected) || len(rej.Recipients) != 2 || len(report) != 2 {
+		t.Fatalf("expected rejection of 2 recipients, got %v / %+v", err, report)
+	}
+}
+
+func TestService_FiltersBeforeSending(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"groupInfo":{"groupId":"G1"}}`))
+	}))
+	defer srv.Close()
+
+	s := NewServiceWithHTTPClient(srv.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"}, srv.Client())
+	s.SetFilters(&RecipientPolicy{Allow: []string{"01011112222"}, Mode: PolicyDrop})
+
+	res, err := s.Send(context.Background(), []Message{{To: "01011112222", Text: "a"}, {To: "01033334444", Text: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(body, "01033334444") || len(res.Filtered) != 1 {
+		t.Fatalf("filter not applied: body=%s filtered=%+v", body, res.Filtered)
+	}
+
+	body = ""
+	if _, err := s.Send(context.Background(), Message{To: "01033334444", Text: "b"}); !errors.Is(err, ErrNoRecipients) {
+		t.Fatalf("expected ErrNoRecipients, got %v", err)
+	}
+	if body != "" {
+		t.Fatal("nothing must be sent when every recipient is filtered")
+	}
+}
@@ -0,0 +1,127 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/idempotency"
+	"github.com/solapi/solapi-go/v2/internal/transport"
+)
+
+// maxResend bounds the re-sends of an idempotent call after network errors.
+const maxResend = 3
+
+// SetIdempotencyStore sets the store of responses for sends made with an
+// IdempotencyKey. It must not be called concurrently with sends.
+func (s *Service) SetIdempotencyStore(store idempotency.Store) {
+	s.idem = store
+}
+
+// SetIdempotencyLookback sets how far back a send with an IdempotencyKey
+// searches for an earlier attempt after a network error. It must not be
+// called concurrently with sends.
+func (s *Service) SetIdempotencyLookback(l idempotency.Lookback) {
+	s.lookback = l
+}
+
+// postSendMany calls POST /messages/v4/send-many/detail. With a key, the
+// messages are tagged with it and a network error is only retried after
+// messages.List shows the earlier attempt did not arrive.
+func (s *Service) postSendMany(ctx context.Context, payload apiSendRequest, key string) (DetailGroupMessageResponse, error) {
+	url := fmt.Sprintf("%s/messages/v4/send-many/detail", s.baseURL)
+	httpReq := transport.DefaultRequest{URL: url, Method: "POST", Operation: "messages.send_many_detail", Messages: len(payload.Messages)}
+	if key == "" {
+		return transport.FetchJSON[apiSendRequest, DetailGroupMessageResponse](s.withTransport(ctx), s.creds, httpReq, &payload)
+	}
+
+	payload.Messages = tagMessages(payload.Messages, key)
+	httpReq.NoNetworkRetry = true
+	since := time.Now().Add(-s.lookback.WindowOrDefault())
+	for attempt := 0; ; attempt++ {
+		res, err := transport.FetchJSON[apiSendRequest, DetailGroupMessageResponse](s.withTransport(ctx), s.creds, httpReq, &payload)
+		if err == nil || attempt == maxResend || !idempotency.UnknownOutcome(ctx, err) {
+			return res, err
+		}
+		prior, found, lerr := s.findSent(ctx, key, firstRecipient(payload.Messages[0]), since)
+		if lerr != nil {
+			// sending again could duplicate; report the original failure
+			return res, err
+		}
+		if found {
+			return prior, nil
+		}
+	}
+}
+
+// tagMessages returns copies of msgs carrying key in their custom fields.
+func tagMessages(msgs []Message, key string) []Message {
+	out := make([]Message, len(msgs))
+	for i, m := range msgs {
+		fields := make(map[string]string, len(m.CustomFields)+1)
+		for k, v := range m.CustomFields {
+			fields[k] = v
+		}
+		fields[idempotency.CustomField] = key
+		m.CustomFields = fields
+		out[i] = m
+	}
+	return out
+}
+
+// firstRecipient returns the number of m's first recipient.
+func firstRecipient(m Message) string {
+	if m.To == "" && len(m.ToList) > 0 {
+		return m.ToList[0]
+	}
+	return m.To
+}
+
+// findSent looks for messages sent with key to the given recipient since the
+// given time and rebuilds the send response from their group.
+func (s *Service) findSent(ctx context.Context, key, to string, since time.Time) (DetailGroupMessageResponse, bool, error) {
+	groupID := ""
+	q := ListQuery{To: to, StartAt: since}
+	for groupID == "" {
+		list, err := s.List(ctx, q)
+		if err != nil {
+			return DetailGroupMessageResponse{}, false, err
+		}
+		for _, m := range list.MessageList {
+			if m.CustomFields[idempotency.CustomField] == key {
+				groupID = m.GroupID
+				break
+			}
+		}
+		if list.NextKey == "" || list.NextKey == q.StartKey {
+			break
+		}
+		q.StartKey = list.NextKey
+	}
+	if groupID == "" {
+		return DetailGroupMessageResponse{}, false, nil
+	}
+
+	res := DetailGroupMessageResponse{GroupID: groupID, Recovered: true}
+	res.GroupInfo.GroupID = groupID
+	q = ListQuery{GroupID: groupID}
+	for {
+		page, err := s.List(ctx, q)
+		if err != nil {
+			return DetailGroupMessageResponse{}, false, err
+		}
+		for id, m := range page.MessageList {
+			if m.MessageID == "" {
+				m.MessageID = id
+			}
+			res.MessageList = append(res.MessageList, MessageListItem{MessageID: m.MessageID, StatusCode: m.StatusCode, CustomFields: m.CustomFields})
+		}
+		if page.NextKey == "" || page.NextKey == q.StartKey {
+			break
+		}
+		q.StartKey = page.NextKey
+	}
+	sort.Slice(res.MessageList, func(i, j int) bool { return res.MessageList[i].MessageID < res.MessageList[j].MessageID })
+	return res, true, nil
+}
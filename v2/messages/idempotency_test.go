@@ -0,0 +1,248 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/idempotency"
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+// dropConnection closes the connection without responding, so the client
+// cannot tell whether the request was processed.
+func dropConnection(t *testing.T, w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestSendManyDetail_IdempotencyRecoversAfterNetworkError(t *testing.T) {
+	var posts atomic.Int32
+	var tagged []Message
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			posts.Add(1)
+			var body apiSendRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			tagged = body.Messages
+			dropConnection(t, w)
+		case r.URL.Query().Get("to") == "01011111111":
+			if r.URL.Query().Get("startDate") == "" {
+				t.Error("lookup should be bounded by date")
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messageList": map[string]any{
+				"M-OTHER": map[string]any{"to": "01011111111", "groupId": "G-OTHER"},
+				"M1":      map[string]any{"to": "01011111111", "groupId": "G1", "customFields": tagged[0].CustomFields},
+			}})
+		case r.URL.Query().Get("groupId") == "G1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"messageList": map[string]any{
+				"M2": map[string]any{"messageId": "M2", "groupId": "G1", "statusCode": "2000", "customFields": tagged[1].CustomFields},
+				"M1": map[string]any{"messageId": "M1", "groupId": "G1", "statusCode": "2000", "customFields": tagged[0].CustomFields},
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	res, err := svc.SendManyDetail(context.Background(), SendRequest{
+		IdempotencyKey: "order-42",
+		Messages: []Message{
+			{To: "01011111111", From: "029999", Text: "a", CustomFields: map[string]string{"order": "42"}},
+			{To: "01022222222", From: "029999", Text: "b"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if posts.Load() != 1 {
+		t.Fatalf("sent %d times, want 1", posts.Load())
+	}
+	if tagged[0].CustomFields[idempotency.CustomField] != "order-42" || tagged[0].CustomFields["order"] != "42" {
+		t.Fatalf("message not tagged: %+v", tagged[0].CustomFields)
+	}
+	if !res.Recovered || res.GroupID != "G1" || res.GroupInfo.GroupID != "G1" {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+	if len(res.MessageList) != 2 || res.MessageList[0].MessageID != "M1" || res.MessageList[1].MessageID != "M2" {
+		t.Fatalf("unexpected message list: %+v", res.MessageList)
+	}
+}
+
+func TestSendManyDetail_IdempotencyLooksPastFirstPage(t *testing.T) {
+	var posts atomic.Int32
+	var tagged []Message
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost:
+			posts.Add(1)
+			var body apiSendRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			tagged = body.Messages
+			dropConnection(t, w)
+		case q.Get("to") == "01011111111" && q.Get("startKey") == "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"nextKey":     "P2",
+				"messageList": map[string]any{"M-OTHER": map[string]any{"to": "01011111111", "groupId": "G-OTHER"}},
+			})
+		case q.Get("to") == "01011111111" && q.Get("startKey") == "P2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"messageList": map[string]any{
+				"M1": map[string]any{"to": "01011111111", "groupId": "G1", "customFields": tagged[0].CustomFields},
+			}})
+		case q.Get("groupId") == "G1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"messageList": map[string]any{
+				"M1": map[string]any{"messageId": "M1", "groupId": "G1", "statusCode": "2000", "customFields": tagged[0].CustomFields},
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	res, err := svc.SendManyDetail(context.Background(), SendRequest{
+		IdempotencyKey: "order-42",
+		Messages:       []Message{{ToList: []string{"01011111111", "01022222222"}, From: "029999", Text: "a"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if posts.Load() != 1 {
+		t.Fatalf("sent %d times, want 1", posts.Load())
+	}
+	if !res.Recovered || res.GroupID != "G1" || len(res.MessageList) != 1 {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+}
+
+func TestSendManyDetail_IdempotencyResendsWhenNotFound(t *testing.T) {
+	var posts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(map[string]any{"messageList": map[string]any{}})
+			return
+		}
+		if posts.Add(1) == 1 {
+			dropConnection(t, w)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{"_id": "G2"}})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	res, err := svc.SendManyDetail(context.Background(), SendRequest{
+		IdempotencyKey: "k1",
+		Messages:       []Message{{To: "01011111111", From: "029999", Text: "a"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if posts.Load() != 2 || res.Recovered || res.GroupInfo.ID != "G2" {
+		t.Fatalf("posts=%d res=%+v", posts.Load(), res)
+	}
+}
+
+func TestSendManyDetail_IdempotencyStoreReturnsStoredResponse(t *testing.T) {
+	var posts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{"_id": "G1", "count": map[string]any{"total": 1}}})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	svc.SetIdempotencyStore(idempotency.NewMemoryStore(0))
+	msg := Message{To: "01011111111", From: "029999", Text: "a"}
+	for i := 0; i < 2; i++ {
+		res, err := svc.Send(context.Background(), msg, SendOptions{IdempotencyKey: "k1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.GroupInfo.ID != "G1" || res.GroupInfo.Count.Total != 1 {
+			t.Fatalf("unexpected response: %+v", res)
+		}
+	}
+	if _, err := svc.Send(context.Background(), msg, SendOptions{IdempotencyKey: "k2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if posts.Load() != 3 {
+		t.Fatalf("sent %d times, want 3", posts.Load())
+	}
+}
+
+// failingStore loses every response.
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) ([]byte, bool, error) { return nil, false, nil }
+
+func (failingStore) Put(context.Context, string, []byte) error { return errors.New("disk full") }
+
+func TestSendManyDetail_IdempotencyStoreFailureKeepsResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{"_id": "G1", "count": map[string]any{"total": 1}}})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	svc.SetIdempotencyStore(failingStore{})
+	res, err := svc.Send(context.Background(), Message{To: "01011111111", From: "029999", Text: "a"}, SendOptions{IdempotencyKey: "k1"})
+	if err != nil {
+		t.Fatalf("a store failure after the send must not be reported as a failed send: %v", err)
+	}
+	if res.GroupInfo.ID != "G1" {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+}
+
+func TestSendManyDetail_IdempotencyLookback(t *testing.T) {
+	var startDate string
+	var posts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			startDate = r.URL.Query().Get("startDate")
+			_ = json.NewEncoder(w).Encode(map[string]any{"messageList": map[string]any{}})
+			return
+		}
+		if posts.Add(1) == 1 {
+			dropConnection(t, w)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{"_id": "G2"}})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	svc.SetIdempotencyLookback(idempotency.Lookback{Window: 6 * time.Hour})
+	before := time.Now()
+	if _, err := svc.Send(context.Background(), Message{To: "01011111111", From: "029999", Text: "a"}, SendOptions{IdempotencyKey: "k1"}); err != nil {
+		t.Fatal(err)
+	}
+	since, err := time.Parse(time.RFC3339, startDate)
+	if err != nil {
+		t.Fatalf("startDate %q: %v", startDate, err)
+	}
+	if d := before.Sub(since); d < 6*time.Hour-time.Minute || d > 6*time.Hour+time.Minute {
+		t.Fatalf("lookup started %v before the send, want 6h", d)
+	}
+}
@@ -0,0 +1,49 @@
+package messages
+
+// Byte limits of text messages as counted by carriers.
+const (
+	SMSMaxBytes = 90
+	LMSMaxBytes = 2000
+)
+
+// Text message types.
+const (
+	TypeSMS = "SMS"
+	TypeLMS = "LMS"
+	TypeMMS = "MMS"
+)
+
+// TextBytes returns the length of s as carriers count it: one byte per ASCII
+// character and two for anything else, as in EUC-KR.
+func TextBytes(s string) int {
+	n := 0
+	for _, r := range s {
+		if r < 0x80 {
+			n++
+		} else {
+			n += 2
+		}
+	}
+	return n
+}
+
+// DetectType returns the text message type m needs: MMS with an image, LMS
+// with a subject or text longer than SMSMaxBytes, SMS otherwise.
+func DetectType(m Message) string {
+	switch {
+	case m.ImageID != "":
+		return TypeMMS
+	case m.Subject != "" || TextBytes(m.Text) > SMSMaxBytes:
+		return TypeLMS
+	}
+	return TypeSMS
+}
+
+// IsTextType reports whether typ is SMS, LMS, MMS or unset.
+func IsTextType(typ string) bool {
+	switch typ {
+	case "", TypeSMS, TypeLMS, TypeMMS:
+		return true
+	}
+	return false
+}
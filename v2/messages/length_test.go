@@ -0,0 +1,25 @@
+package messages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectType(t *testing.T) {
+	cases := []struct {
+		m    Message
+		want string
+	}{
+		{Message{Text: strings.Repeat("a", 90)}, TypeSMS},
+		{Message{Text: strings.Repeat("a", 91)}, TypeLMS},
+		{Message{Text: strings.Repeat("가", 45)}, TypeSMS},
+		{Message{Text: strings.Repeat("가", 46)}, TypeLMS},
+		{Message{Text: "a", Subject: "s"}, TypeLMS},
+		{Message{Text: "a", ImageID: "img"}, TypeMMS},
+	}
+	for _, c := range cases {
+		if got := DetectType(c.m); got != c.want {
+			t.Errorf("DetectType(%d bytes) = %s, want %s", TextBytes(c.m.Text), got, c.want)
+		}
+	}
+}
@@ -32,9 +32,13 @@ func (s *Service) List(ctx context.Context, q ListQuery) (MessageListResponse, e
 	}
 	if q.StartDate != "" {
 		params.Set("startDate", q.StartDate)
+	} else if !q.StartAt.IsZero() {
+		params.Set("startDate", FormatDate(q.StartAt))
 	}
 	if q.EndDate != "" {
 		params.Set("endDate", q.EndDate)
+	} else if !q.EndAt.IsZero() {
+		params.Set("endDate", FormatDate(q.EndAt))
 	}
 	if q.StartKey != "" {
 		params.Set("startKey", q.StartKey)
@@ -48,6 +52,7 @@ func (s *Service) List(ctx context.Context, q ListQuery) (MessageListResponse, e
 		urlStr += "?" + enc
 	}
 
-	req := transport.DefaultRequest{URL: urlStr, Method: "GET"}
+	req := transport.DefaultRequest{URL: urlStr, Method: "GET", Operation: "messages.list"}
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[struct{}, MessageListResponse](ctx, s.creds, req, nil)
 }
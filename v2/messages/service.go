@@ -3,60 +3,78 @@ package messages
 import (
 	"context"
 	"errors"
-	"fmt"
 	"runtime"
 
 	"net/http"
 
+	"github.com/solapi/solapi-go/v2/idempotency"
 	"github.com/solapi/solapi-go/v2/internal/auth"
-	"github.com/solapi/solapi-go/v2/internal/transport"
 )
 
 type Service struct {
 	baseURL    string
-	creds      auth.AuthenticationParameter
+	creds      auth.CredentialsProvider
 	httpClient *http.Client
+	filters    []RecipientFilter
+	idem       idempotency.Store
+	lookback   idempotency.Lookback
+	defaults   Defaults
 }
 
-func NewService(baseURL string, creds auth.AuthenticationParameter) *Service {
+func NewService(baseURL string, creds auth.CredentialsProvider) *Service {
 	return &Service{baseURL: baseURL, creds: creds, httpClient: http.DefaultClient}
 }
 
 // Send accepts Message, []Message, or SendRequest and normalizes to the API call.
 func (s *Service) Send(ctx context.Context, input any, opts ...SendOptions) (DetailGroupMessageResponse, error) {
+	req, err := NewSendRequest(input, opts...)
+	if err != nil {
+		return DetailGroupMessageResponse{}, err
+	}
+	return s.SendManyDetail(ctx, req)
+}
+
+// NewSendRequest normalizes the inputs accepted by Send into a SendRequest.
+// Options are ignored when input is already a SendRequest.
+func NewSendRequest(input any, opts ...SendOptions) (SendRequest, error) {
+	var o SendOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	var msgs []Message
 	switch v := input.(type) {
 	case SendRequest:
-		return s.SendManyDetail(ctx, v)
+		return v, nil
 	case Message:
-		var o SendOptions
-		if len(opts) > 0 {
-			o = opts[0]
-		}
-		return s.SendManyDetail(ctx, SendRequest{
-			Messages:        []Message{v},
-			AllowDuplicates: o.AllowDuplicates,
-			ScheduledDate:   o.ScheduledDate,
-			ShowMessageList: o.ShowMessageList,
-			AppId:           o.AppId,
-		})
+		msgs = []Message{v}
 	case []Message:
-		var o SendOptions
-		if len(opts) > 0 {
-			o = opts[0]
-		}
-		return s.SendManyDetail(ctx, SendRequest{
-			Messages:        v,
-			AllowDuplicates: o.AllowDuplicates,
-			ScheduledDate:   o.ScheduledDate,
-			ShowMessageList: o.ShowMessageList,
-			AppId:           o.AppId,
-		})
+		msgs = v
 	default:
-		return DetailGroupMessageResponse{}, errors.New("unsupported input type for Send")
+		return SendRequest{}, errors.New("unsupported input type for Send")
 	}
+	return SendRequest{
+		Messages:        msgs,
+		AllowDuplicates: o.AllowDuplicates,
+		ScheduledDate:   o.ScheduledDate,
+		ShowMessageList: o.ShowMessageList,
+		AppId:           o.AppId,
+		ScheduleAt:      o.ScheduleAt,
+		FailOnPartial:   o.FailOnPartial,
+		IdempotencyKey:  o.IdempotencyKey,
+	}, nil
+}
+
+// SetFilters replaces the recipient filters applied before every send. It
+// must not be called concurrently with sends.
+func (s *Service) SetFilters(filters ...RecipientFilter) {
+	s.filters = filters
 }
 
 func (s *Service) SendManyDetail(ctx context.Context, req SendRequest) (DetailGroupMessageResponse, error) {
+	if req.AppId == "" {
+		req.AppId = s.defaults.AppId
+	}
+	req.Messages = s.defaults.Apply(req.Messages)
 	// validate recipients: each message must have non-empty To or non-empty ToList
 	for _, m := range req.Messages {
 		if m.To == "" && len(m.ToList) == 0 {
@@ -71,6 +89,20 @@ func (s *Service) SendManyDetail(ctx context.Context, req SendRequest) (DetailGr
 		}
 	}
 
+	scheduledDate, err := resolveSchedule(req.ScheduledDate, req.ScheduleAt)
+	if err != nil {
+		return DetailGroupMessageResponse{}, err
+	}
+
+	msgs, index, filtered, err := filterRecipients(ctx, req.Messages, s.filters)
+	if err != nil {
+		return DetailGroupMessageResponse{Filtered: filtered}, err
+	}
+	if len(msgs) == 0 {
+		return DetailGroupMessageResponse{Filtered: filtered}, ErrNoRecipients
+	}
+	req.Messages = msgs
+
 	ag := &apiAgent{
 		SDKVersion: "go/2.0.0",
 		OSPlatform: runtime.GOOS + " | " + runtime.Version(),
@@ -81,20 +113,40 @@ func (s *Service) SendManyDetail(ctx context.Context, req SendRequest) (DetailGr
 	payload := apiSendRequest{
 		Messages:        req.Messages,
 		AllowDuplicates: req.AllowDuplicates,
-		ScheduledDate:   req.ScheduledDate,
+		ScheduledDate:   scheduledDate,
 		ShowMessageList: req.ShowMessageList,
 		Agent:           ag,
 	}
-	url := fmt.Sprintf("%s/messages/v4/send-many/detail", s.baseURL)
-	httpReq := transport.DefaultRequest{URL: url, Method: "POST"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
-	res, err := transport.FetchJSON[apiSendRequest, DetailGroupMessageResponse](ctx, s.creds, httpReq, &payload)
+	res, err := idempotency.Do(ctx, s.idem, "messages.send_many_detail", req.IdempotencyKey, func(ctx context.Context) (DetailGroupMessageResponse, error) {
+		return s.postSendMany(ctx, payload, req.IdempotencyKey)
+	})
 	if err != nil {
 		return DetailGroupMessageResponse{}, err
 	}
+	res.Filtered = filtered
 	c := res.GroupInfo.Count
 	if len(res.FailedMessageList) > 0 && c.Total == c.RegisteredFailed {
 		return DetailGroupMessageResponse{}, &MessageNotReceivedError{FailedMessageList: res.FailedMessageList, TotalCount: len(res.FailedMessageList)}
 	}
+	if req.FailOnPartial && len(res.FailedMessageList) > 0 {
+		failures, unmatched := mapFailures(req.Messages, res.FailedMessageList)
+		if index != nil {
+			// report positions in the caller's messages, not the filtered ones
+			for i := range failures {
+				failures[i].Index = index[failures[i].Index]
+			}
+		}
+		return res, &PartialSendError{Response: res, Failures: failures, Unmatched: unmatched}
+	}
 	return res, nil
 }
+
+// ResendFailures sends again the retryable messages of a partial failure.
+// retryable may be nil to use IsRetryableFailure.
+func (s *Service) ResendFailures(ctx context.Context, perr *PartialSendError, retryable func(FailedMessage) bool, opts ...SendOptions) (DetailGroupMessageResponse, error) {
+	msgs := perr.RetryMessages(retryable)
+	if len(msgs) == 0 {
+		return DetailGroupMessageResponse{}, errors.New("no retryable failures to resend")
+	}
+	return s.Send(ctx, msgs, opts...)
+}
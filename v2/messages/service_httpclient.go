@@ -1,16 +1,24 @@
 package messages
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/solapi/solapi-go/v2/internal/auth"
+	"github.com/solapi/solapi-go/v2/internal/transport"
 )
 
 // NewServiceWithHTTPClient initializes Service with a custom *http.Client.
 // If httpClient is nil, http.DefaultClient is used.
-func NewServiceWithHTTPClient(baseURL string, creds auth.AuthenticationParameter, httpClient *http.Client) *Service {
+func NewServiceWithHTTPClient(baseURL string, creds auth.CredentialsProvider, httpClient *http.Client) *Service {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 	return &Service{baseURL: baseURL, creds: creds, httpClient: httpClient}
 }
+
+// withTransport stores the service's http.Client and the client options
+// attached to it in ctx.
+func (s *Service) withTransport(ctx context.Context) context.Context {
+	return transport.WithClient(ctx, s.httpClient)
+}
@@ -0,0 +1,129 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestSendManyDetail_FailOnPartialMapsFailures(t *testing.T) {
+	var resent []any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		if resent == nil && len(body["messages"].([]any)) == 3 {
+			resent = []any{}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"groupInfo": map[string]any{"count": map[string]any{"total": 4, "registeredFailed": 2}},
+				"failedMessageList": []any{
+					map[string]any{"to": "01022222222", "from": "029999", "statusCode": "3040"},
+					map[string]any{"to": "01033333333", "from": "029999", "statusCode": "1062"},
+				},
+			})
+			return
+		}
+		resent = body["messages"].([]any)
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{"count": map[string]any{"total": 1}}})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	req := SendRequest{
+		FailOnPartial: true,
+		Messages: []Message{
+			{To: "01011111111", From: "029999", Text: "a"},
+			{ToList: []string{"010-1111-1111", "010-2222-2222"}, From: "029999", Text: "b", CustomFields: map[string]string{"order": "42"}},
+			{To: "01033333333", From: "029999", Text: "c"},
+		},
+	}
+	res, err := svc.SendManyDetail(context.Background(), req)
+	var perr *PartialSendError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected PartialSendError, got %v", err)
+	}
+	if res.GroupInfo.Count.Total != 4 {
+		t.Fatalf("response should be returned alongside the error")
+	}
+	if len(perr.Failures) != 2 || len(perr.Unmatched) != 0 {
+		t.Fatalf("unexpected mapping: %+v", perr)
+	}
+	first := perr.Failures[0]
+	if first.Index != 1 || first.Message.To != "010-2222-2222" || first.Message.ToList != nil || first.Message.CustomFields["order"] != "42" {
+		t.Fatalf("unexpected first failure: %+v", first)
+	}
+	if perr.Failures[1].Index != 2 {
+		t.Fatalf("unexpected second failure index: %d", perr.Failures[1].Index)
+	}
+
+	if _, err := svc.ResendFailures(context.Background(), perr, nil); err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	if len(resent) != 1 {
+		t.Fatalf("only the retryable failure should be resent, got %d", len(resent))
+	}
+}
+
+func TestSendManyDetail_PartialWithoutStrictModeSucceeds(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"groupInfo":         map[string]any{"count": map[string]any{"total": 2, "registeredFailed": 1}},
+			"failedMessageList": []any{map[string]any{"to": "010"}},
+		})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	if _, err := svc.Send(context.Background(), []Message{{To: "010"}, {To: "011"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIsRetryableFailure(t *testing.T) {
+	for code, want := range map[string]bool{
+		"3040": true,
+		"3059": true,
+		"1062": false,
+		"":     false,
+		"9999": false,
+	} {
+		if got := IsRetryableFailure(FailedMessage{StatusCode: code}); got != want {
+			t.Errorf("IsRetryableFailure(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestSendManyDetail_PartialIndexIgnoresFilteredMessages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"groupInfo":         map[string]any{"count": map[string]any{"total": 2, "registeredFailed": 1}},
+			"failedMessageList": []any{map[string]any{"to": "01033333333", "statusCode": "3040"}},
+		})
+	}))
+	defer ts.Close()
+
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	svc.SetFilters(&RecipientPolicy{Allow: []string{"01022222222", "01033333333"}, Mode: PolicyDrop})
+	_, err := svc.SendManyDetail(context.Background(), SendRequest{
+		FailOnPartial: true,
+		Messages: []Message{
+			{To: "01011111111", From: "029999", Text: "dropped"},
+			{To: "01022222222", From: "029999", Text: "sent"},
+			{To: "01033333333", From: "029999", Text: "failed"},
+		},
+	})
+	var perr *PartialSendError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected PartialSendError, got %v", err)
+	}
+	if len(perr.Failures) != 1 || perr.Failures[0].Index != 2 || perr.Failures[0].Message.Text != "failed" {
+		t.Fatalf("unexpected failures: %+v", perr.Failures)
+	}
+}
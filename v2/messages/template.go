@@ -0,0 +1,229 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var (
+	// ErrMissingVariable matches a *MissingVariableError.
+	ErrMissingVariable = errors.New("messages: missing template variable")
+	// ErrTextTooLong is returned when rendered text exceeds LMSMaxBytes.
+	ErrTextTooLong = errors.New("messages: text exceeds LMS limit")
+)
+
+// MissingVariableError is returned when data lacks a variable the template
+// uses.
+type MissingVariableError struct {
+	// Name is the variable; it is empty when text/template did not say.
+	Name string
+	Err  error
+}
+
+func (e *MissingVariableError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%v: %v", ErrMissingVariable, e.Err)
+	}
+	return fmt.Sprintf("%v %q", ErrMissingVariable, e.Name)
+}
+
+func (e *MissingVariableError) Is(target error) bool { return target == ErrMissingVariable }
+
+func (e *MissingVariableError) Unwrap() error { return e.Err }
+
+var placeholderRe = regexp.MustCompile(`#\{([^{}]+)\}`)
+
+// Template renders personalized text messages from a base message whose
+// Text and Subject contain variables. It is safe for concurrent use.
+//
+// Templates from ParseTemplate use #{name} placeholders, the syntax of
+// AlimTalk variables; ParseGoTemplate accepts text/template syntax. Both
+// fail on variables missing from the data. After rendering, the type of a
+// text message is recomputed with DetectType, so a body that grows past
+// SMSMaxBytes is sent as LMS.
+type Template struct {
+	base    Message
+	text    renderer
+	subject renderer
+	vars    []string
+}
+
+type renderer func(data any) (string, error)
+
+// ParseTemplate compiles base.Text and base.Subject with #{name}
+// placeholders. Render accepts map[string]string or map[string]any data.
+func ParseTemplate(base Message) (*Template, error) {
+	t := &Template{base: base}
+	seen := map[string]bool{}
+	for _, s := range []string{base.Subject, base.Text} {
+		matches := placeholderRe.FindAllStringSubmatch(s, -1)
+		if strings.Count(s, "#{") != len(matches) {
+			return nil, fmt.Errorf("messages: unterminated placeholder in %q", s)
+		}
+		for _, m := range matches {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				t.vars = append(t.vars, m[1])
+			}
+		}
+	}
+	t.text = placeholderRenderer(base.Text)
+	t.subject = placeholderRenderer(base.Subject)
+	return t, nil
+}
+
+// ParseGoTemplate compiles base.Text and base.Subject as text/template
+// templates with funcs available. Missing map keys are errors.
+func ParseGoTemplate(base Message, funcs template.FuncMap) (*Template, error) {
+	t := &Template{base: base}
+	var err error
+	if t.text, err = goRenderer("text", base.Text, funcs); err != nil {
+		return nil, err
+	}
+	if t.subject, err = goRenderer("subject", base.Subject, funcs); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Variables returns the #{name} variables in order of appearance. It is
+// nil for text/template templates.
+func (t *Template) Variables() []string {
+	return append([]string(nil), t.vars...)
+}
+
+// Render returns the base message with Text and Subject rendered from data.
+func (t *Template) Render(data any) (Message, error) {
+	m := t.base
+	var err error
+	if m.Text, err = t.text(data); err != nil {
+		return Message{}, err
+	}
+	if m.Subject, err = t.subject(data); err != nil {
+		return Message{}, err
+	}
+	if IsTextType(t.base.Type) && m.KakaoOptions == nil && m.VoiceOptions == nil && m.FaxOptions == nil {
+		if n := TextBytes(m.Text); n > LMSMaxBytes {
+			return Message{}, fmt.Errorf("%w: %d bytes", ErrTextTooLong, n)
+		}
+		m.Type = DetectType(m)
+	}
+	return m, nil
+}
+
+// TemplateRow is one recipient of Template.Messages.
+type TemplateRow struct {
+	To   string
+	Data any
+	// CustomFields are merged over the base message's custom fields.
+	CustomFields map[string]string
+}
+
+// TemplateRowError reports the row a render failed for.
+type TemplateRowError struct {
+	Index int
+	Err   error
+}
+
+func (e *TemplateRowError) Error() string { return fmt.Sprintf("row %d: %v", e.Index, e.Err) }
+
+func (e *TemplateRowError) Unwrap() error { return e.Err }
+
+// Messages renders one message per row. Rows that fail are skipped and
+// reported together as *TemplateRowError values joined in the error.
+func (t *Template) Messages(rows []TemplateRow) ([]Message, error) {
+	out := make([]Message, 0, len(rows))
+	var errs []error
+	for i, row := range rows {
+		m, err := t.Render(row.Data)
+		if err != nil {
+			errs = append(errs, &TemplateRowError{Index: i, Err: err})
+			continue
+		}
+		m.To, m.ToList = row.To, nil
+		if len(row.CustomFields) > 0 {
+			fields := make(map[string]string, len(m.CustomFields)+len(row.CustomFields))
+			for k, v := range m.CustomFields {
+				fields[k] = v
+			}
+			for k, v := range row.CustomFields {
+				fields[k] = v
+			}
+			m.CustomFields = fields
+		}
+		out = append(out, m)
+	}
+	return out, errors.Join(errs...)
+}
+
+// SendRequest renders rows with Messages and builds a request with opts, so
+// that a template and data rows can be passed straight to SendManyDetail.
+// It fails if any row fails.
+func (t *Template) SendRequest(rows []TemplateRow, opts ...SendOptions) (SendRequest, error) {
+	msgs, err := t.Messages(rows)
+	if err != nil {
+		return SendRequest{}, err
+	}
+	return NewSendRequest(msgs, opts...)
+}
+
+func placeholderRenderer(s string) renderer {
+	if !strings.Contains(s, "#{") {
+		return func(any) (string, error) { return s, nil }
+	}
+	return func(data any) (string, error) {
+		var missing string
+		out := placeholderRe.ReplaceAllStringFunc(s, func(p string) string {
+			name := p[2 : len(p)-1]
+			v, ok := lookup(data, name)
+			if !ok && missing == "" {
+				missing = name
+			}
+			return v
+		})
+		if missing != "" {
+			return "", &MissingVariableError{Name: missing}
+		}
+		return out, nil
+	}
+}
+
+func lookup(data any, name string) (string, bool) {
+	switch d := data.(type) {
+	case map[string]string:
+		v, ok := d[name]
+		return v, ok
+	case map[string]any:
+		v, ok := d[name]
+		if !ok || v == nil {
+			return "", false
+		}
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+var noEntryRe = regexp.MustCompile(`map has no entry for key "([^"]*)"`)
+
+func goRenderer(name, s string, funcs template.FuncMap) (renderer, error) {
+	if !strings.Contains(s, "{{") {
+		return func(any) (string, error) { return s, nil }, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcs).Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return func(data any) (string, error) {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			if m := noEntryRe.FindStringSubmatch(err.Error()); m != nil {
+				return "", &MissingVariableError{Name: m[1], Err: err}
+			}
+			return "", err
+		}
+		return b.String(), nil
+	}, nil
+}
@@ -0,0 +1,108 @@
+package messages
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplate_Placeholders(t *testing.T) {
+	tmpl, err := ParseTemplate(Message{From: "029302266", Subject: "#{name}님 안내", Text: "#{name}님, #{count}건이 도착했습니다."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := tmpl.Variables(); len(v) != 2 || v[0] != "name" || v[1] != "count" {
+		t.Fatalf("variables: %v", v)
+	}
+	m, err := tmpl.Render(map[string]any{"name": "홍길동", "count": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Text != "홍길동님, 3건이 도착했습니다." || m.Subject != "홍길동님 안내" || m.From != "029302266" {
+		t.Fatalf("rendered: %+v", m)
+	}
+	if m.Type != TypeLMS {
+		t.Fatalf("subject should make LMS, got %q", m.Type)
+	}
+
+	_, err = tmpl.Render(map[string]string{"name": "홍길동"})
+	var merr *MissingVariableError
+	if !errors.Is(err, ErrMissingVariable) || !errors.As(err, &merr) || merr.Name != "count" {
+		t.Fatalf("expected missing count, got %v", err)
+	}
+
+	if _, err := ParseTemplate(Message{Text: "#{name"}); err == nil {
+		t.Fatal("expected error for unterminated placeholder")
+	}
+}
+
+func TestTemplate_RecomputesType(t *testing.T) {
+	tmpl, _ := ParseTemplate(Message{Type: TypeSMS, Text: "안녕하세요 #{name}님"})
+	short, err := tmpl.Render(map[string]string{"name": "김"})
+	if err != nil || short.Type != TypeSMS {
+		t.Fatalf("short: %+v %v", short, err)
+	}
+	long, err := tmpl.Render(map[string]string{"name": strings.Repeat("가", 40)})
+	if err != nil || long.Type != TypeLMS {
+		t.Fatalf("long: %q %v", long.Type, err)
+	}
+	if _, err := tmpl.Render(map[string]string{"name": strings.Repeat("가", 1000)}); !errors.Is(err, ErrTextTooLong) {
+		t.Fatalf("expected ErrTextTooLong, got %v", err)
+	}
+
+	ata, _ := ParseTemplate(Message{Type: "ATA", Text: "#{name}", KakaoOptions: &KakaoOptions{PfID: "PF"}})
+	if m, _ := ata.Render(map[string]string{"name": strings.Repeat("가", 100)}); m.Type != "ATA" {
+		t.Fatalf("non-text type changed to %q", m.Type)
+	}
+}
+
+func TestTemplate_GoTemplate(t *testing.T) {
+	type order struct {
+		Name  string
+		Items []string
+	}
+	tmpl, err := ParseGoTemplate(Message{Text: `{{.Name}}님 주문: {{join .Items ", "}}`}, template.FuncMap{"join": strings.Join})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := tmpl.Render(order{Name: "홍길동", Items: []string{"사과", "배"}})
+	if err != nil || m.Text != "홍길동님 주문: 사과, 배" || m.Type != TypeSMS {
+		t.Fatalf("rendered: %+v %v", m, err)
+	}
+
+	mt, _ := ParseGoTemplate(Message{Text: "{{.name}}"}, nil)
+	_, err = mt.Render(map[string]string{})
+	var merr *MissingVariableError
+	if !errors.As(err, &merr) || merr.Name != "name" {
+		t.Fatalf("expected missing name, got %v", err)
+	}
+	if _, err := ParseGoTemplate(Message{Text: "{{.name"}, nil); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestTemplate_SendRequest(t *testing.T) {
+	tmpl, _ := ParseTemplate(Message{From: "029302266", Text: "#{name}님", CustomFields: map[string]string{"campaign": "c1"}})
+	rows := []TemplateRow{
+		{To: "01011112222", Data: map[string]string{"name": "갑"}, CustomFields: map[string]string{"id": "1"}},
+		{To: "01033334444", Data: map[string]string{}},
+		{To: "01055556666", Data: map[string]string{"name": "병"}},
+	}
+	msgs, err := tmpl.Messages(rows)
+	var rerr *TemplateRowError
+	if !errors.As(err, &rerr) || rerr.Index != 1 || !errors.Is(err, ErrMissingVariable) {
+		t.Fatalf("expected row 1 error, got %v", err)
+	}
+	if len(msgs) != 2 || msgs[0].To != "01011112222" || msgs[0].CustomFields["id"] != "1" || msgs[0].CustomFields["campaign"] != "c1" || msgs[1].Text != "병님" {
+		t.Fatalf("messages: %+v", msgs)
+	}
+	if _, err := tmpl.SendRequest(rows); err == nil {
+		t.Fatal("SendRequest should fail on a bad row")
+	}
+
+	req, err := tmpl.SendRequest(rows[:1], SendOptions{AppId: "app"})
+	if err != nil || len(req.Messages) != 1 || req.AppId != "app" {
+		t.Fatalf("request: %+v %v", req, err)
+	}
+}
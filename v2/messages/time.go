@@ -0,0 +1,109 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// KST is the Asia/Seoul offset the API assumes for dates without a timezone.
+var KST = time.FixedZone("KST", 9*60*60)
+
+// ScheduleWindowMonths is how far ahead the API accepts scheduled sends.
+const ScheduleWindowMonths = 6
+
+var (
+	ErrScheduleInPast = errors.New("messages: scheduled time must be in the future")
+	ErrScheduleTooFar = fmt.Errorf("messages: scheduled time must be within %d months", ScheduleWindowMonths)
+)
+
+// timeNow is a seam for tests.
+var timeNow = time.Now
+
+// dateLayouts are tried in order by ParseDate. Layouts without an offset are
+// interpreted in KST.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// FormatDate encodes t as ISO-8601 (RFC 3339) in UTC, the form used for every
+// date sent to the API.
+func FormatDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ParseDate parses a date returned by the API. The result is normalized to UTC.
+func ParseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, KST); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("messages: invalid date %q", s)
+}
+
+// ValidateSchedule reports whether t is an acceptable scheduled send time:
+// strictly in the future and no more than ScheduleWindowMonths ahead.
+func ValidateSchedule(t time.Time) error {
+	now := timeNow()
+	if !t.After(now) {
+		return ErrScheduleInPast
+	}
+	if t.After(now.AddDate(0, ScheduleWindowMonths, 0)) {
+		return ErrScheduleTooFar
+	}
+	return nil
+}
+
+// resolveSchedule merges the string and typed schedule fields of a request.
+func resolveSchedule(date string, at time.Time) (string, error) {
+	if at.IsZero() {
+		return date, nil
+	}
+	if date != "" {
+		return "", errors.New("messages: set either ScheduledDate or ScheduleAt, not both")
+	}
+	if err := ValidateSchedule(at); err != nil {
+		return "", err
+	}
+	return FormatDate(at), nil
+}
+
+// parseOrZero returns the parsed date or the zero time when s is empty or
+// malformed.
+func parseOrZero(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := ParseDate(s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Parsed accessors return the zero time when the field is empty or malformed.
+
+func (m Message) CreatedAt() time.Time   { return parseOrZero(m.DateCreated) }
+func (m Message) UpdatedAt() time.Time   { return parseOrZero(m.DateUpdated) }
+func (m Message) ProcessedAt() time.Time { return parseOrZero(m.DateProcessed) }
+func (m Message) ReportedAt() time.Time  { return parseOrZero(m.DateReported) }
+func (m Message) ReceivedAt() time.Time  { return parseOrZero(m.DateReceived) }
+
+func (g GroupInfo) SentAt() time.Time      { return parseOrZero(g.DateSent) }
+func (g GroupInfo) ScheduledAt() time.Time { return parseOrZero(g.ScheduledDate) }
+func (g GroupInfo) CompletedAt() time.Time { return parseOrZero(g.DateCompleted) }
+func (g GroupInfo) CreatedAt() time.Time   { return parseOrZero(g.DateCreated) }
+func (g GroupInfo) UpdatedAt() time.Time   { return parseOrZero(g.DateUpdated) }
+
+func (d DetailGroupMessageResponse) SentAt() time.Time      { return parseOrZero(d.DateSent) }
+func (d DetailGroupMessageResponse) ScheduledAt() time.Time { return parseOrZero(d.ScheduledDate) }
+func (d DetailGroupMessageResponse) CompletedAt() time.Time { return parseOrZero(d.DateCompleted) }
+func (d DetailGroupMessageResponse) CreatedAt() time.Time   { return parseOrZero(d.DateCreated) }
+func (d DetailGroupMessageResponse) UpdatedAt() time.Time   { return parseOrZero(d.DateUpdated) }
+
+func (l LogEntry) CreatedAt() time.Time { return parseOrZero(l.CreateAt) }
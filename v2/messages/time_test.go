@@ -0,0 +1,88 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/internal/auth"
+)
+
+func TestParseDate_Layouts(t *testing.T) {
+	want := time.Date(2025, 9, 14, 3, 4, 5, 0, time.UTC)
+	cases := []string{
+		"2025-09-14T03:04:05Z",
+		"2025-09-14T12:04:05+09:00",
+		"2025-09-14 12:04:05",
+		"2025-09-14T12:04:05.000",
+	}
+	for _, s := range cases {
+		got, err := ParseDate(s)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s, err)
+		}
+		if !got.Equal(want) || got.Location() != time.UTC {
+			t.Fatalf("%s: got %v, want %v", s, got, want)
+		}
+	}
+	if _, err := ParseDate("yesterday"); err == nil {
+		t.Fatalf("expected error for invalid date")
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = old }()
+
+	if err := ValidateSchedule(now.Add(-time.Minute)); !errors.Is(err, ErrScheduleInPast) {
+		t.Fatalf("expected ErrScheduleInPast, got %v", err)
+	}
+	if err := ValidateSchedule(now.AddDate(0, 7, 0)); !errors.Is(err, ErrScheduleTooFar) {
+		t.Fatalf("expected ErrScheduleTooFar, got %v", err)
+	}
+	if err := ValidateSchedule(now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSend_ScheduleAtEncodedAsUTC(t *testing.T) {
+	var seen string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		seen, _ = body["scheduledDate"].(string)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"groupInfo": map[string]any{}})
+	}))
+	defer ts.Close()
+
+	at := time.Now().Add(time.Hour).In(KST).Truncate(time.Second)
+	svc := NewService(ts.URL, auth.AuthenticationParameter{ApiKey: "k", ApiSecret: "s"})
+	if _, err := svc.Send(context.Background(), Message{To: "010"}, SendOptions{ScheduleAt: at}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen != at.UTC().Format(time.RFC3339) {
+		t.Fatalf("scheduledDate = %q", seen)
+	}
+
+	_, err := svc.Send(context.Background(), Message{To: "010"}, SendOptions{ScheduleAt: at, ScheduledDate: "2025-01-01"})
+	if err == nil {
+		t.Fatalf("expected error when both schedule fields are set")
+	}
+}
+
+func TestGroupInfo_ParsedAccessors(t *testing.T) {
+	g := GroupInfo{DateSent: "2025-09-14T03:04:05.123Z", DateCompleted: "bogus"}
+	if g.SentAt().IsZero() {
+		t.Fatalf("SentAt should parse")
+	}
+	if !g.CompletedAt().IsZero() || !g.ScheduledAt().IsZero() {
+		t.Fatalf("malformed or empty dates should yield zero time")
+	}
+}
@@ -1,6 +1,9 @@
 package messages
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Request types
 
@@ -27,6 +30,17 @@ type SendOptions struct {
 	AllowDuplicates *bool
 	ScheduledDate   string
 	ShowMessageList *bool
+
+	// ScheduleAt is a typed alternative to ScheduledDate. It is validated with
+	// ValidateSchedule and encoded with FormatDate.
+	ScheduleAt time.Time
+
+	// FailOnPartial makes Send return a *PartialSendError when any message
+	// fails to be registered; see SendRequest.
+	FailOnPartial bool
+
+	// IdempotencyKey deduplicates the send; see SendRequest.
+	IdempotencyKey string
 }
 
 type KakaoButton struct {
@@ -90,6 +104,10 @@ type Message struct {
 	Type         string            `json:"type,omitempty"`
 	AutoType     *bool             `json:"autoTypeDetect,omitempty"`
 
+	// Ad marks the message as an advertisement for the adpolicy package.
+	// It is not sent to the API; Kakao messages use KakaoOptions.AdFlag.
+	Ad bool `json:"-"`
+
 	// Common response-side fields (ignored on send if empty)
 	MessageID     string `json:"messageId,omitempty"`
 	GroupID       string `json:"groupId,omitempty"`
@@ -114,6 +132,20 @@ type SendRequest struct {
 
 	// AppId is provided by SDK users; Service builds agent internally.
 	AppId string `json:"-"`
+
+	// ScheduleAt is a typed alternative to ScheduledDate; see SendOptions.
+	ScheduleAt time.Time `json:"-"`
+
+	// FailOnPartial enables strict mode: if some messages fail to be
+	// registered, the response is returned together with a *PartialSendError
+	// instead of a nil error.
+	FailOnPartial bool `json:"-"`
+
+	// IdempotencyKey makes the send safe to repeat. Messages are tagged with
+	// it in the idempotency.CustomField custom field, retries after network
+	// errors look for the tagged messages before sending again, and with an
+	// idempotency store a repeated key returns the stored response.
+	IdempotencyKey string `json:"-"`
 }
 
 // Response types
@@ -319,6 +351,15 @@ type DetailGroupMessageResponse struct {
 
 	DateCreated string `json:"dateCreated"`
 	DateUpdated string `json:"dateUpdated"`
+
+	// Filtered reports recipients dropped or rewritten by recipient filters
+	// before the request was sent. It is not part of the API response.
+	Filtered []FilteredRecipient `json:"-"`
+
+	// Recovered is set when a send with an idempotency key failed in transit
+	// and the response was rebuilt from the messages found on the server.
+	// Only group and message IDs are filled in then.
+	Recovered bool `json:"-"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for DetailGroupMessageResponse
@@ -493,6 +534,9 @@ type ListQuery struct {
 	DateType  string
 	StartDate string
 	EndDate   string
+	// StartAt and EndAt are used when StartDate and EndDate are empty.
+	StartAt time.Time
+	EndAt   time.Time
 
 	StartKey string
 	Limit    int
@@ -0,0 +1,137 @@
+// Package otelsolapi adapts the SDK's instrumentation hooks to OpenTelemetry.
+// It lives in its own module so the core SDK stays free of dependencies.
+//
+//	inst, err := otelsolapi.New()
+//	c := client.NewClient(key, secret).WithInstrumentation(inst)
+package otelsolapi
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/solapi/solapi-go/v2/instrumentation"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/solapi/solapi-go/v2/otelsolapi"
+
+// Metric names.
+const (
+	MetricDuration = "solapi.client.duration"
+	MetricMessages = "solapi.client.messages.submitted"
+)
+
+// AttrOperation is the metric attribute carrying the operation name.
+const AttrOperation = "solapi.operation"
+
+type config struct {
+	tp trace.TracerProvider
+	mp metric.MeterProvider
+}
+
+// Option configures New.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider; the global one is the default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tp = tp }
+}
+
+// WithMeterProvider sets the meter provider; the global one is the default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.mp = mp }
+}
+
+// Instrumentation implements instrumentation.Instrumentation with
+// OpenTelemetry client spans, a latency histogram and a message counter.
+type Instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	messages metric.Int64Counter
+}
+
+var _ instrumentation.Instrumentation = (*Instrumentation)(nil)
+
+// New returns an Instrumentation using the configured providers.
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := config{tp: otel.GetTracerProvider(), mp: otel.GetMeterProvider()}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	meter := cfg.mp.Meter(ScopeName)
+	duration, err := meter.Float64Histogram(MetricDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of SOLAPI calls, including retries."))
+	if err != nil {
+		return nil, err
+	}
+	messages, err := meter.Int64Counter(MetricMessages,
+		metric.WithUnit("{message}"),
+		metric.WithDescription("Messages submitted by successful SOLAPI send and group append calls, including registration failures."))
+	if err != nil {
+		return nil, err
+	}
+	return &Instrumentation{tracer: cfg.tp.Tracer(ScopeName), duration: duration, messages: messages}, nil
+}
+
+func (i *Instrumentation) StartSpan(ctx context.Context, operation string, attrs ...instrumentation.Attribute) (context.Context, instrumentation.Span) {
+	ctx, s := i.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convert(attrs)...))
+	return ctx, span{s}
+}
+
+func (i *Instrumentation) RecordLatency(ctx context.Context, operation string, d time.Duration, attrs ...instrumentation.Attribute) {
+	kv := append(convert(attrs), attribute.String(AttrOperation, operation))
+	i.duration.Record(ctx, d.Seconds(), metric.WithAttributes(kv...))
+}
+
+func (i *Instrumentation) AddMessages(ctx context.Context, operation string, n int, attrs ...instrumentation.Attribute) {
+	// per-group ids would explode metric cardinality
+	kv := []attribute.KeyValue{attribute.String(AttrOperation, operation)}
+	for _, a := range convert(attrs) {
+		if a.Key != instrumentation.AttrGroupID {
+			kv = append(kv, a)
+		}
+	}
+	i.messages.Add(ctx, int64(n), metric.WithAttributes(kv...))
+}
+
+type span struct{ s trace.Span }
+
+func (s span) SetAttributes(attrs ...instrumentation.Attribute) {
+	s.s.SetAttributes(convert(attrs)...)
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+func convert(attrs []instrumentation.Attribute) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			out = append(out, attribute.String(a.Key, v))
+		case int:
+			out = append(out, attribute.Int(a.Key, v))
+		case int64:
+			out = append(out, attribute.Int64(a.Key, v))
+		case float64:
+			out = append(out, attribute.Float64(a.Key, v))
+		case bool:
+			out = append(out, attribute.Bool(a.Key, v))
+		}
+	}
+	return out
+}
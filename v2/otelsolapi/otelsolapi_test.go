@@ -0,0 +1,67 @@
+package otelsolapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/solapi/solapi-go/v2/instrumentation"
+)
+
+func TestInstrumentation(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	inst, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	group := instrumentation.String(instrumentation.AttrGroupID, "G1")
+	_, s := inst.StartSpan(ctx, "groups.add_messages", group)
+	s.SetAttributes(instrumentation.Int(instrumentation.AttrHTTPStatus, 500))
+	s.End(errors.New("boom"))
+	inst.RecordLatency(ctx, "groups.add_messages", 250*time.Millisecond)
+	inst.AddMessages(ctx, "groups.add_messages", 7, group)
+
+	ended := spans.Ended()
+	if len(ended) != 1 || ended[0].Name() != "groups.add_messages" || ended[0].Status().Code != codes.Error {
+		t.Fatalf("unexpected spans: %+v", ended)
+	}
+	if len(ended[0].Attributes()) != 2 {
+		t.Fatalf("unexpected attributes: %v", ended[0].Attributes())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				dp := sum.DataPoints[0]
+				if dp.Value != 7 {
+					t.Fatalf("messages = %d", dp.Value)
+				}
+				if _, ok := dp.Attributes.Value(instrumentation.AttrGroupID); ok {
+					t.Fatal("group id must not be a metric attribute")
+				}
+			}
+		}
+	}
+	if !found[MetricDuration] || !found[MetricMessages] {
+		t.Fatalf("missing metrics: %v", found)
+	}
+}
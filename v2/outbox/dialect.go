@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect adapts the SQL to a database.
+type Dialect int
+
+const (
+	// Postgres claims rows with SELECT ... FOR UPDATE SKIP LOCKED, so any
+	// number of workers can share a table.
+	Postgres Dialect = iota
+	// SQLite relies on SQLite's single writer for exclusive claims.
+	SQLite
+)
+
+// rebind rewrites ? placeholders to $n for Postgres.
+func (d Dialect) rebind(q string) string {
+	if d != Postgres {
+		return q
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Schema returns the statements creating table and its index.
+func (d Dialect) Schema(table string) []string {
+	id := "BIGSERIAL PRIMARY KEY"
+	if d == SQLite {
+		id = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id              %s,
+	message         TEXT NOT NULL,
+	ad              INTEGER NOT NULL DEFAULT 0,
+	app_id          TEXT NOT NULL DEFAULT '',
+	status          TEXT NOT NULL DEFAULT 'pending',
+	attempts        INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at BIGINT NOT NULL,
+	lease_owner     TEXT NOT NULL DEFAULT '',
+	lease_until     BIGINT NOT NULL DEFAULT 0,
+	message_id      TEXT NOT NULL DEFAULT '',
+	group_id        TEXT NOT NULL DEFAULT '',
+	last_error      TEXT NOT NULL DEFAULT '',
+	created_at      BIGINT NOT NULL,
+	updated_at      BIGINT NOT NULL
+)`, table, id),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_due ON %s (status, next_attempt_at)`, table, table),
+	}
+}
+
+// claimQuery leases up to limit due rows and returns them.
+// Arguments: owner, lease_until, now, now, limit.
+func (d Dialect) claimQuery(table string) string {
+	lock := ""
+	if d == Postgres {
+		lock = " FOR UPDATE SKIP LOCKED"
+	}
+	return d.rebind(fmt.Sprintf(`UPDATE %[1]s SET lease_owner = ?, lease_until = ?, attempts = attempts + 1
+WHERE id IN (
+	SELECT id FROM %[1]s
+	WHERE status = 'pending' AND next_attempt_at <= ? AND lease_until <= ?
+	ORDER BY id LIMIT ?%[2]s
+)
+RETURNING id, message, ad, app_id, attempts`, table, lock))
+}
@@ -0,0 +1,486 @@
+// Package outbox makes sends reliable across process crashes with the
+// transactional outbox pattern: messages are written to a table in the same
+// transaction as the business data, and a worker sends them afterwards.
+//
+//	tx, _ := db.BeginTx(ctx, nil)
+//	// ... write the order ...
+//	err := box.Enqueue(ctx, tx, messages.Message{To: to, From: from, Text: text})
+//	tx.Commit()
+//
+//	go box.Run(ctx, c.Messages)
+//
+// The worker leases rows so a crashed worker's batch is picked up again once
+// the lease expires. A send is abandoned before its lease runs out, and a
+// worker only records outcomes for rows it still holds. Sends that fail are
+// retried with exponential backoff. Each batch carries an idempotency key
+// derived from its row IDs, so when a response is lost in transit the client
+// looks for the batch on the server instead of sending it twice.
+// Only database/sql is used; pick the Dialect matching the driver.
+package outbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+// Row statuses.
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	// StatusDropped marks messages removed by the client's recipient filters.
+	StatusDropped = "dropped"
+)
+
+// IDField is the custom field carrying the outbox row ID of a message, used
+// to match API results to rows.
+const IDField = "outboxId"
+
+// Defaults applied to zero Options fields.
+const (
+	DefaultTable        = "solapi_outbox"
+	DefaultBatchSize    = 100
+	DefaultLease        = time.Minute
+	DefaultMaxAttempts  = 10
+	DefaultPollInterval = time.Second
+)
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *sql.Conn, so Enqueue can join
+// the caller's transaction.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Sender sends a batch; *messages.Service implements it.
+type Sender interface {
+	SendManyDetail(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error)
+}
+
+// Options configures an Outbox.
+type Options struct {
+	Table     string
+	BatchSize int
+	// Lease is how long a claimed batch is reserved for one worker.
+	Lease       time.Duration
+	MaxAttempts int
+	// Backoff returns the delay before the given attempt is retried. The
+	// default doubles from one second up to five minutes.
+	Backoff      func(attempt int) time.Duration
+	PollInterval time.Duration
+	// Owner identifies the worker in lease_owner; defaults to a random ID.
+	Owner string
+	// OnError is called by Run with the errors of a batch, typically from
+	// the database, before Run carries on polling. Nil ignores them.
+	OnError func(error)
+}
+
+// Entry is a row of the outbox.
+type Entry struct {
+	ID        int64
+	Message   messages.Message
+	AppID     string
+	Status    string
+	Attempts  int
+	MessageID string
+	GroupID   string
+	LastError string
+}
+
+// Outbox stores pending messages in a table and sends them.
+type Outbox struct {
+	db  *sql.DB
+	d   Dialect
+	opt Options
+	now func() time.Time
+}
+
+// New returns an Outbox on db.
+func New(db *sql.DB, d Dialect, opt Options) *Outbox {
+	if opt.Table == "" {
+		opt.Table = DefaultTable
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = DefaultBatchSize
+	}
+	if opt.Lease <= 0 {
+		opt.Lease = DefaultLease
+	}
+	if opt.MaxAttempts <= 0 {
+		opt.MaxAttempts = DefaultMaxAttempts
+	}
+	if opt.Backoff == nil {
+		opt.Backoff = defaultBackoff
+	}
+	if opt.PollInterval <= 0 {
+		opt.PollInterval = DefaultPollInterval
+	}
+	if opt.Owner == "" {
+		opt.Owner = "worker-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return &Outbox{db: db, d: d, opt: opt, now: time.Now}
+}
+
+func defaultBackoff(attempt int) time.Duration {
+	d := time.Second << min(attempt-1, 9)
+	return min(d, 5*time.Minute)
+}
+
+// CreateSchema creates the outbox table if it does not exist.
+func (o *Outbox) CreateSchema(ctx context.Context) error {
+	for _, stmt := range o.d.Schema(o.opt.Table) {
+		if _, err := o.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Enqueue stores msgs for sending through ex, typically the caller's
+// transaction. A message with ToList is stored as one row per recipient.
+func (o *Outbox) Enqueue(ctx context.Context, ex Execer, msgs ...messages.Message) error {
+	return o.EnqueueApp(ctx, ex, "", msgs...)
+}
+
+// EnqueueApp is like Enqueue and sends the messages with the given AppId.
+func (o *Outbox) EnqueueApp(ctx context.Context, ex Execer, appID string, msgs ...messages.Message) error {
+	now := o.now().UnixMilli()
+	q := o.d.rebind(fmt.Sprintf(`INSERT INTO %s (message, ad, app_id, next_attempt_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`, o.opt.Table))
+	for _, m := range msgs {
+		recipients := m.ToList
+		if len(recipients) == 0 {
+			recipients = []string{m.To}
+		}
+		for _, to := range recipients {
+			if to == "" {
+				return errors.New("outbox: recipient is required")
+			}
+			m.To, m.ToList = to, nil
+			b, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			// Message.Ad is not part of the message JSON
+			if _, err := ex.ExecContext(ctx, q, string(b), boolInt(m.Ad), appID, now, now, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Run processes batches until ctx is done, sleeping PollInterval whenever
+// nothing is due. It returns ctx.Err() on shutdown.
+func (o *Outbox) Run(ctx context.Context, s Sender) error {
+	for {
+		n, err := o.ProcessBatch(ctx, s)
+		if err != nil && ctx.Err() == nil {
+			// database errors are transient from the worker's point of view
+			if o.opt.OnError != nil {
+				o.opt.OnError(err)
+			}
+			n = 0
+		}
+		if n == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(o.opt.PollInterval):
+			}
+		}
+	}
+}
+
+type claimed struct {
+	id       int64
+	msg      messages.Message
+	appID    string
+	attempts int
+}
+
+// ProcessBatch claims due rows, sends them grouped by AppId and records the
+// outcome. It returns the number of rows claimed.
+func (o *Outbox) ProcessBatch(ctx context.Context, s Sender) (int, error) {
+	// Sends must end before another worker can claim the rows again; the
+	// margin leaves time to record the outcome.
+	sendCtx, cancel := context.WithDeadline(ctx, time.Now().Add(o.opt.Lease-o.opt.Lease/10))
+	defer cancel()
+	rows, err := o.claim(ctx)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	byApp := map[string][]claimed{}
+	var apps []string
+	for _, r := range rows {
+		if _, ok := byApp[r.appID]; !ok {
+			apps = append(apps, r.appID)
+		}
+		byApp[r.appID] = append(byApp[r.appID], r)
+	}
+	for _, app := range apps {
+		if err := o.send(ctx, sendCtx, s, app, byApp[app]); err != nil {
+			return len(rows), err
+		}
+	}
+	return len(rows), nil
+}
+
+func (o *Outbox) claim(ctx context.Context) ([]claimed, error) {
+	now := o.now()
+	rs, err := o.db.QueryContext(ctx, o.d.claimQuery(o.opt.Table),
+		o.opt.Owner, now.Add(o.opt.Lease).UnixMilli(), now.UnixMilli(), now.UnixMilli(), o.opt.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+	var out []claimed
+	for rs.Next() {
+		var c claimed
+		var raw string
+		var ad int
+		if err := rs.Scan(&c.id, &raw, &ad, &c.appID, &c.attempts); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(raw), &c.msg); err != nil {
+			return nil, fmt.Errorf("outbox: row %d: %w", c.id, err)
+		}
+		c.msg.Ad = ad != 0
+		out = append(out, c)
+	}
+	return out, rs.Err()
+}
+
+// batchKey derives the idempotency key of a batch from its row IDs, so a
+// send whose outcome is unknown is not duplicated when repeated.
+func batchKey(batch []claimed) string {
+	h := sha256.New()
+	for _, r := range batch {
+		fmt.Fprintf(h, "%d,", r.id)
+	}
+	return "outbox-" + hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// send sends one batch within sendCtx and records per-row outcomes with ctx.
+func (o *Outbox) send(ctx, sendCtx context.Context, s Sender, appID string, batch []claimed) error {
+	msgs := make([]messages.Message, len(batch))
+	byID := make(map[string]claimed, len(batch))
+	for i, r := range batch {
+		m := r.msg
+		fields := make(map[string]string, len(m.CustomFields)+1)
+		for k, v := range m.CustomFields {
+			fields[k] = v
+		}
+		key := strconv.FormatInt(r.id, 10)
+		fields[IDField] = key
+		m.CustomFields = fields
+		msgs[i] = m
+		byID[key] = r
+	}
+	show := true
+	res, err := s.SendManyDetail(sendCtx, messages.SendRequest{
+		Messages:        msgs,
+		AppId:           appID,
+		ShowMessageList: &show,
+		FailOnPartial:   true,
+		IdempotencyKey:  batchKey(batch),
+	})
+
+	var partial *messages.PartialSendError
+	var notReceived *messages.MessageNotReceivedError
+	switch {
+	case err == nil:
+	case errors.As(err, &partial):
+		res = partial.Response
+		for _, f := range partial.Failures {
+			r, ok := byID[f.Message.CustomFields[IDField]]
+			if !ok {
+				continue
+			}
+			delete(byID, f.Message.CustomFields[IDField])
+			if err := o.fail(ctx, r, res.GroupInfo.GroupID, f.Failed.StatusCode+" "+f.Failed.StatusMessage, messages.IsRetryableFailure(f.Failed)); err != nil {
+				return err
+			}
+		}
+	case errors.As(err, &notReceived):
+		for _, r := range batch {
+			retry := true
+			for _, f := range notReceived.FailedMessageList {
+				if f.To == r.msg.To {
+					retry = messages.IsRetryableFailure(f)
+					break
+				}
+			}
+			if err := o.fail(ctx, r, "", err.Error(), retry); err != nil {
+				return err
+			}
+		}
+		return nil
+	case errors.Is(err, messages.ErrRecipientRejected):
+		// drop the rejected rows only and send the others again
+		var rejected *messages.RecipientRejectedError
+		dropped := map[int]bool{}
+		if errors.As(err, &rejected) {
+			for _, f := range rejected.Recipients {
+				if f.Index >= len(batch) || dropped[f.Index] {
+					continue
+				}
+				dropped[f.Index] = true
+				reason := f.Reason
+				if reason == "" {
+					reason = err.Error()
+				}
+				if err := o.finish(ctx, batch[f.Index].id, StatusDropped, "", "", reason); err != nil {
+					return err
+				}
+			}
+		}
+		if len(dropped) == 0 {
+			// nothing to tell the rows apart by; retry the batch later
+			for _, r := range batch {
+				if err := o.fail(ctx, r, "", err.Error(), true); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		rest := make([]claimed, 0, len(batch)-len(dropped))
+		for i, r := range batch {
+			if !dropped[i] {
+				rest = append(rest, r)
+			}
+		}
+		if len(rest) == 0 {
+			return nil
+		}
+		return o.send(ctx, sendCtx, s, appID, rest)
+	case errors.Is(err, messages.ErrNoRecipients):
+		for _, r := range batch {
+			if err := o.finish(ctx, r.id, StatusDropped, "", "", err.Error()); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// the outcome is unknown or the batch was refused as a whole
+		for _, r := range batch {
+			if err := o.fail(ctx, r, "", err.Error(), true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, f := range res.Filtered {
+		if f.Action != messages.RecipientDrop || f.Index >= len(batch) {
+			continue
+		}
+		r := batch[f.Index]
+		delete(byID, strconv.FormatInt(r.id, 10))
+		if err := o.finish(ctx, r.id, StatusDropped, "", "", f.Reason); err != nil {
+			return err
+		}
+	}
+	groupID := res.GroupInfo.GroupID
+	if groupID == "" {
+		groupID = res.GroupID
+	}
+	for _, item := range res.MessageList {
+		key := item.CustomFields[IDField]
+		r, ok := byID[key]
+		if !ok {
+			continue
+		}
+		delete(byID, key)
+		if err := o.finish(ctx, r.id, StatusSent, item.MessageID, groupID, ""); err != nil {
+			return err
+		}
+	}
+	if partial != nil && len(partial.Unmatched) > 0 {
+		// some of the remaining rows failed, but which is unknown
+		retry := true
+		for _, f := range partial.Unmatched {
+			retry = retry && messages.IsRetryableFailure(f)
+		}
+		f := partial.Unmatched[0]
+		for _, r := range byID {
+			if err := o.fail(ctx, r, groupID, f.StatusCode+" "+f.StatusMessage, retry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// accepted without a per-message result
+	for _, r := range byID {
+		if err := o.finish(ctx, r.id, StatusSent, "", groupID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fail schedules a retry, or marks the row failed when retry is false or
+// attempts are exhausted. Like finish, it leaves rows whose lease passed to
+// another worker untouched.
+func (o *Outbox) fail(ctx context.Context, r claimed, groupID, reason string, retry bool) error {
+	if !retry || r.attempts >= o.opt.MaxAttempts {
+		return o.finish(ctx, r.id, StatusFailed, "", groupID, reason)
+	}
+	now := o.now()
+	q := o.d.rebind(fmt.Sprintf(`UPDATE %s SET next_attempt_at = ?, lease_owner = '', lease_until = 0, last_error = ?, updated_at = ? WHERE id = ? AND lease_owner = ?`, o.opt.Table))
+	_, err := o.db.ExecContext(ctx, q, now.Add(o.opt.Backoff(r.attempts)).UnixMilli(), reason, now.UnixMilli(), r.id, o.opt.Owner)
+	return err
+}
+
+func (o *Outbox) finish(ctx context.Context, id int64, status, messageID, groupID, reason string) error {
+	q := o.d.rebind(fmt.Sprintf(`UPDATE %s SET status = ?, message_id = ?, group_id = ?, last_error = ?, lease_owner = '', lease_until = 0, updated_at = ? WHERE id = ? AND lease_owner = ?`, o.opt.Table))
+	_, err := o.db.ExecContext(ctx, q, status, messageID, groupID, reason, o.now().UnixMilli(), id, o.opt.Owner)
+	return err
+}
+
+// List returns up to limit rows with the given status, oldest first. An
+// empty status lists every row.
+func (o *Outbox) List(ctx context.Context, status string, limit int) ([]Entry, error) {
+	q := fmt.Sprintf(`SELECT id, message, ad, app_id, status, attempts, message_id, group_id, last_error FROM %s`, o.opt.Table)
+	var args []any
+	if status != "" {
+		q += ` WHERE status = ?`
+		args = append(args, status)
+	}
+	q += ` ORDER BY id LIMIT ?`
+	args = append(args, limit)
+	rs, err := o.db.QueryContext(ctx, o.d.rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+	var out []Entry
+	for rs.Next() {
+		var e Entry
+		var raw string
+		var ad int
+		if err := rs.Scan(&e.ID, &raw, &ad, &e.AppID, &e.Status, &e.Attempts, &e.MessageID, &e.GroupID, &e.LastError); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(raw), &e.Message); err != nil {
+			return nil, fmt.Errorf("outbox: row %d: %w", e.ID, err)
+		}
+		e.Message.Ad = ad != 0
+		out = append(out, e)
+	}
+	return out, rs.Err()
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,404 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/solapi/solapi-go/v2/messages"
+	_ "modernc.org/sqlite"
+)
+
+type senderFunc func(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error)
+
+func (f senderFunc) SendManyDetail(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+	return f(ctx, req)
+}
+
+func accepted(req messages.SendRequest) messages.DetailGroupMessageResponse {
+	var res messages.DetailGroupMessageResponse
+	res.GroupInfo.GroupID = "G1"
+	for i, m := range req.Messages {
+		res.MessageList = append(res.MessageList, messages.MessageListItem{
+			MessageID:    "M" + string(rune('A'+i)),
+			StatusCode:   "2000",
+			CustomFields: m.CustomFields,
+		})
+	}
+	return res
+}
+
+func newOutbox(t *testing.T, opt Options) (*Outbox, *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	o := New(db, SQLite, opt)
+	if err := o.CreateSchema(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return o, db
+}
+
+func TestEnqueueInTransaction(t *testing.T) {
+	o, db := newOutbox(t, Options{})
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Enqueue(ctx, tx, messages.Message{ToList: []string{"01011112222", "01033334444"}, From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := o.List(ctx, "", 10); len(got) != 0 {
+		t.Fatalf("rolled back rows visible: %+v", got)
+	}
+
+	tx, _ = db.BeginTx(ctx, nil)
+	if err := o.Enqueue(ctx, tx, messages.Message{ToList: []string{"01011112222", "01033334444"}, From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := o.List(ctx, StatusPending, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Message.To != "01011112222" || got[1].Message.To != "01033334444" || got[0].Message.ToList != nil {
+		t.Fatalf("entries: %+v", got)
+	}
+}
+
+func TestProcessBatchRecordsResults(t *testing.T) {
+	o, db := newOutbox(t, Options{BatchSize: 2})
+	ctx := context.Background()
+	for _, to := range []string{"01000000001", "01000000002", "01000000003"} {
+		if err := o.Enqueue(ctx, db, messages.Message{To: to, From: "029302266", Text: "hi", CustomFields: map[string]string{"order": to}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var sizes []int
+	s := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		sizes = append(sizes, len(req.Messages))
+		if !req.FailOnPartial || req.ShowMessageList == nil || !*req.ShowMessageList {
+			t.Fatalf("request options: %+v", req)
+		}
+		for _, m := range req.Messages {
+			if m.CustomFields[IDField] == "" || m.CustomFields["order"] != m.To {
+				t.Fatalf("custom fields: %+v", m.CustomFields)
+			}
+		}
+		return accepted(req), nil
+	})
+	for {
+		n, err := o.ProcessBatch(ctx, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			break
+		}
+	}
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 1 {
+		t.Fatalf("batches: %v", sizes)
+	}
+	got, _ := o.List(ctx, StatusSent, 10)
+	if len(got) != 3 || got[0].GroupID != "G1" || got[0].MessageID != "MA" || got[2].MessageID != "MA" || got[0].Attempts != 1 {
+		t.Fatalf("sent: %+v", got)
+	}
+	if _, ok := got[0].Message.CustomFields[IDField]; ok {
+		t.Fatal("stored message was modified")
+	}
+}
+
+func TestProcessBatchRetriesWithBackoff(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	o, db := newOutbox(t, Options{MaxAttempts: 2, Backoff: func(int) time.Duration { return time.Minute }})
+	o.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := o.Enqueue(ctx, db, messages.Message{To: "01000000001", From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	s := senderFunc(func(context.Context, messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		calls++
+		return messages.DetailGroupMessageResponse{}, errors.New("connection reset")
+	})
+
+	if n, err := o.ProcessBatch(ctx, s); n != 1 || err != nil {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if n, _ := o.ProcessBatch(ctx, s); n != 0 {
+		t.Fatal("row retried before backoff elapsed")
+	}
+	now = now.Add(time.Minute)
+	if n, _ := o.ProcessBatch(ctx, s); n != 1 {
+		t.Fatal("row not retried after backoff")
+	}
+	got, _ := o.List(ctx, "", 10)
+	if calls != 2 || got[0].Status != StatusFailed || got[0].Attempts != 2 || got[0].LastError != "connection reset" {
+		t.Fatalf("calls=%d entry=%+v", calls, got[0])
+	}
+}
+
+func TestProcessBatchPartialFailure(t *testing.T) {
+	o, db := newOutbox(t, Options{})
+	ctx := context.Background()
+	for _, to := range []string{"01000000001", "01000000002", "01000000003"} {
+		if err := o.Enqueue(ctx, db, messages.Message{To: to, From: "029302266", Text: "hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		res := accepted(messages.SendRequest{Messages: req.Messages[:1]})
+		return res, &messages.PartialSendError{
+			Response: res,
+			Failures: []messages.PartialFailure{
+				{Index: 1, Message: req.Messages[1], Failed: messages.FailedMessage{To: req.Messages[1].To, StatusCode: "1020", StatusMessage: "invalid"}},
+				{Index: 2, Message: req.Messages[2], Failed: messages.FailedMessage{To: req.Messages[2].To, StatusCode: "3059", StatusMessage: "busy"}},
+			},
+		}
+	})
+	if _, err := o.ProcessBatch(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := o.List(ctx, "", 10)
+	if got[0].Status != StatusSent || got[1].Status != StatusFailed || got[2].Status != StatusPending {
+		t.Fatalf("statuses: %s %s %s", got[0].Status, got[1].Status, got[2].Status)
+	}
+	if got[1].LastError != "1020 invalid" || got[2].LastError != "3059 busy" {
+		t.Fatalf("errors: %q %q", got[1].LastError, got[2].LastError)
+	}
+}
+
+func TestProcessBatchDropsFiltered(t *testing.T) {
+	o, db := newOutbox(t, Options{})
+	ctx := context.Background()
+	if err := o.Enqueue(ctx, db, messages.Message{To: "01000000001", From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	s := senderFunc(func(context.Context, messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		return messages.DetailGroupMessageResponse{}, messages.ErrNoRecipients
+	})
+	if _, err := o.ProcessBatch(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := o.List(ctx, "", 10)
+	if got[0].Status != StatusDropped {
+		t.Fatalf("entry: %+v", got[0])
+	}
+}
+
+func TestProcessBatchDropsOnlyRejected(t *testing.T) {
+	o, db := newOutbox(t, Options{})
+	ctx := context.Background()
+	for _, to := range []string{"01000000001", "01099999999", "01000000003"} {
+		if err := o.Enqueue(ctx, db, messages.Message{To: to, From: "029302266", Text: "hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	policy := &messages.RecipientPolicy{AllowPrefixes: []string{"0100000"}}
+	var sent []string
+	s := senderFunc(func(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		if _, _, err := messages.FilterRecipients(ctx, req.Messages, policy); err != nil {
+			return messages.DetailGroupMessageResponse{}, err
+		}
+		for _, m := range req.Messages {
+			sent = append(sent, m.To)
+		}
+		return accepted(req), nil
+	})
+	if _, err := o.ProcessBatch(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := o.List(ctx, "", 10)
+	if got[0].Status != StatusSent || got[1].Status != StatusDropped || got[2].Status != StatusSent {
+		t.Fatalf("statuses: %s %s %s", got[0].Status, got[1].Status, got[2].Status)
+	}
+	if got[1].LastError != "not in recipient allowlist" || len(sent) != 2 {
+		t.Fatalf("dropped %+v, sent %v", got[1], sent)
+	}
+}
+
+func TestProcessBatchUnmatchedFailures(t *testing.T) {
+	o, db := newOutbox(t, Options{})
+	ctx := context.Background()
+	for _, to := range []string{"01000000001", "01000000002"} {
+		if err := o.Enqueue(ctx, db, messages.Message{To: to, From: "029302266", Text: "hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		res := accepted(messages.SendRequest{Messages: req.Messages[:1]})
+		return res, &messages.PartialSendError{
+			Response:  res,
+			Unmatched: []messages.FailedMessage{{To: "unknown", StatusCode: "1020", StatusMessage: "invalid"}},
+		}
+	})
+	if _, err := o.ProcessBatch(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := o.List(ctx, "", 10)
+	if got[0].Status != StatusSent || got[1].Status != StatusFailed || got[1].LastError != "1020 invalid" {
+		t.Fatalf("entries: %+v", got)
+	}
+}
+
+func TestProcessBatchIdempotencyKey(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	o, db := newOutbox(t, Options{Backoff: func(int) time.Duration { return time.Minute }})
+	o.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := o.Enqueue(ctx, db, messages.Message{To: "01000000001", From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	s := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		keys = append(keys, req.IdempotencyKey)
+		return messages.DetailGroupMessageResponse{}, errors.New("timeout")
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := o.ProcessBatch(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Minute)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("a repeated batch must reuse its key: %q", keys)
+	}
+}
+
+func TestRunReportsErrors(t *testing.T) {
+	o, db := newOutbox(t, Options{PollInterval: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	var got error
+	o.opt.OnError = func(err error) {
+		got = err
+		cancel()
+	}
+	db.Close()
+	if err := o.Run(ctx, senderFunc(nil)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run: %v", err)
+	}
+	if got == nil {
+		t.Fatal("database error not reported")
+	}
+}
+
+func TestLeaseExcludesClaimedRows(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	o, db := newOutbox(t, Options{Lease: time.Minute})
+	o.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := o.Enqueue(ctx, db, messages.Message{To: "01000000001", From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	// a worker that crashed after claiming
+	rows, err := o.claim(ctx)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("claim: %v %v", rows, err)
+	}
+	if rows, _ := o.claim(ctx); len(rows) != 0 {
+		t.Fatal("leased row claimed twice")
+	}
+	now = now.Add(time.Minute)
+	if rows, _ := o.claim(ctx); len(rows) != 1 || rows[0].attempts != 2 {
+		t.Fatalf("expired lease not reclaimed: %+v", rows)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	if got := Postgres.rebind("a = ? AND b = ?"); got != "a = $1 AND b = $2" {
+		t.Fatal(got)
+	}
+	if got := SQLite.rebind("a = ?"); got != "a = ?" {
+		t.Fatal(got)
+	}
+}
+
+func TestExpiredLeaseOutcomeIgnored(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	slow, db := newOutbox(t, Options{Lease: time.Minute, Owner: "slow"})
+	fast := New(db, SQLite, Options{Lease: time.Minute, Owner: "fast"})
+	slow.now = func() time.Time { return now }
+	fast.now = slow.now
+	ctx := context.Background()
+	if err := slow.Enqueue(ctx, db, messages.Message{To: "01000000001", From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var sends int
+	s := senderFunc(func(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		sends++
+		if sends == 1 {
+			// the slow worker's lease runs out mid-send
+			now = now.Add(2 * time.Minute)
+			if n, err := fast.ProcessBatch(ctx, senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+				return accepted(req), nil
+			})); n != 1 || err != nil {
+				t.Fatalf("reclaim: %d %v", n, err)
+			}
+			return messages.DetailGroupMessageResponse{}, errors.New("timeout")
+		}
+		return accepted(req), nil
+	})
+	if _, err := slow.ProcessBatch(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := slow.List(ctx, "", 10)
+	if got[0].Status != StatusSent || got[0].MessageID != "MA" || got[0].LastError != "" {
+		t.Fatalf("slow worker overwrote the outcome: %+v", got[0])
+	}
+}
+
+func TestSendDeadlineWithinLease(t *testing.T) {
+	o, db := newOutbox(t, Options{Lease: time.Minute})
+	ctx := context.Background()
+	if err := o.Enqueue(ctx, db, messages.Message{To: "01000000001", From: "029302266", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	var deadline time.Time
+	start := time.Now()
+	s := senderFunc(func(ctx context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		deadline, _ = ctx.Deadline()
+		return accepted(req), nil
+	})
+	if _, err := o.ProcessBatch(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if deadline.IsZero() || deadline.After(start.Add(time.Minute)) {
+		t.Fatalf("send deadline %v not bounded by the lease", deadline)
+	}
+}
+
+func TestAdFlagPreserved(t *testing.T) {
+	o, db := newOutbox(t, Options{})
+	ctx := context.Background()
+	if err := o.Enqueue(ctx, db, messages.Message{To: "01000000001", From: "029302266", Text: "(광고) sale", Ad: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := o.List(ctx, "", 10); !got[0].Message.Ad {
+		t.Fatalf("List lost the ad flag: %+v", got[0].Message)
+	}
+	var ad bool
+	s := senderFunc(func(_ context.Context, req messages.SendRequest) (messages.DetailGroupMessageResponse, error) {
+		ad = req.Messages[0].Ad
+		return accepted(req), nil
+	})
+	if _, err := o.ProcessBatch(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if !ad {
+		t.Fatal("queued ad sent without its ad flag")
+	}
+}
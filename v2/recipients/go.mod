@@ -0,0 +1,9 @@
+module github.com/solapi/solapi-go/v2/recipients
+
+go 1.25.1
+
+require github.com/solapi/solapi-go/v2 v2.0.0
+
+require golang.org/x/text v0.28.0
+
+replace github.com/solapi/solapi-go/v2 => ../
@@ -0,0 +1,342 @@
+// Package recipients turns CSV recipient lists into personalized messages.
+//
+// Spreadsheets exported in Korea are often encoded in CP949 (EUC-KR); Parse
+// detects this by default. Columns are mapped by header name to the
+// recipient number, custom fields and Kakao template variables, and the
+// message text can be rendered per row from #{column} placeholders:
+//
+//	res, err := recipients.Parse(f, recipients.Options{
+//		Base:     messages.Message{From: "029302266"},
+//		Mapping:  recipients.Mapping{To: "전화번호", CustomFields: map[string]string{"customerId": "회원번호"}},
+//		Template: "#{이름}님, 주문하신 상품이 발송되었습니다.",
+//	})
+//
+// Rows that cannot be used are skipped and reported in Result.Errors.
+package recipients
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/solapi/solapi-go/v2/messages"
+	"golang.org/x/text/encoding/korean"
+)
+
+// Encoding is the character encoding of the input.
+type Encoding int
+
+const (
+	// EncodingAuto uses UTF-8 when the input is valid UTF-8 and CP949
+	// otherwise.
+	EncodingAuto Encoding = iota
+	EncodingUTF8
+	// EncodingCP949 also reads EUC-KR, which it extends.
+	EncodingCP949
+)
+
+var (
+	// ErrMissingColumn is returned by Parse when a mapped column is not in
+	// the header.
+	ErrMissingColumn = errors.New("recipients: column not found")
+	// ErrInvalidNumber reports a row whose number is not a phone number.
+	ErrInvalidNumber = errors.New("recipients: invalid phone number")
+	// ErrDuplicate reports a row repeating an earlier row's number.
+	ErrDuplicate = errors.New("recipients: duplicate phone number")
+	// ErrMissingValue reports a row with an empty value a placeholder uses.
+	ErrMissingValue = errors.New("recipients: missing value")
+)
+
+// Mapping maps header names to message fields.
+type Mapping struct {
+	// To is the column holding the recipient number. It is required.
+	To string
+	// CustomFields maps custom field names to columns.
+	CustomFields map[string]string
+	// Variables maps Kakao template variables, e.g. "#{이름}", to columns.
+	Variables map[string]string
+}
+
+// Options configures Parse.
+type Options struct {
+	Encoding Encoding
+	// Comma is the field delimiter; zero means ','.
+	Comma   rune
+	Mapping Mapping
+	// Base is copied into every message; To, Text, CustomFields and
+	// KakaoOptions.Variables are filled in per row. With a Template,
+	// #{column} placeholders in Base.Subject are rendered too.
+	Base messages.Message
+	// Template renders the message text with messages.ParseTemplate,
+	// replacing #{column} with the row's value, and the type of text
+	// messages is recomputed. Rows with an empty value for a placeholder are
+	// rejected. An empty Template keeps Base.Text.
+	Template string
+	// KeepDuplicates disables the removal of repeated numbers.
+	KeepDuplicates bool
+	// Validate, when set, runs on every message built from a row; an error
+	// rejects the row.
+	Validate func(m messages.Message) error
+}
+
+// RowError describes a rejected row.
+type RowError struct {
+	// Line is the 1-based line of the row in the input, counting the header.
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: column %q (%q): %v", e.Line, e.Column, e.Value, e.Err)
+}
+
+func (e *RowError) Unwrap() error { return e.Err }
+
+// Result is the outcome of Parse.
+type Result struct {
+	Messages []messages.Message
+	// Lines holds the input line of each message.
+	Lines  []int
+	Errors []*RowError
+	// Rows is the number of non-empty data rows read.
+	Rows int
+}
+
+// ParseFile is like Parse but reads the named file.
+func ParseFile(name string, opt Options) (Result, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+	return Parse(f, opt)
+}
+
+// Parse reads a CSV with a header row from r and builds one message per
+// data row. Errors affecting single rows are collected in the result; the
+// returned error is for unreadable input or a header missing mapped columns.
+func Parse(r io.Reader, opt Options) (Result, error) {
+	if opt.Mapping.To == "" {
+		return Result{}, errors.New("recipients: Mapping.To is required")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Result{}, err
+	}
+	data, err = decode(data, opt.Encoding)
+	if err != nil {
+		return Result{}, err
+	}
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	if opt.Comma != 0 {
+		cr.Comma = opt.Comma
+	}
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return Result{}, errors.New("recipients: empty input")
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if _, ok := cols[h]; !ok {
+			cols[h] = i
+		}
+	}
+	var need []string
+	need = append(need, opt.Mapping.To)
+	need = append(need, valuesOf(opt.Mapping.CustomFields)...)
+	need = append(need, valuesOf(opt.Mapping.Variables)...)
+	var tmpl *messages.Template
+	if opt.Template != "" {
+		// Base.Text may hold AlimTalk variables the API fills in, so it is
+		// only rendered when replaced by Template
+		base := opt.Base
+		base.Text = opt.Template
+		if tmpl, err = messages.ParseTemplate(base); err != nil {
+			return Result{}, err
+		}
+		need = append(need, tmpl.Variables()...)
+	}
+	for _, c := range need {
+		if _, ok := cols[c]; !ok {
+			return Result{}, fmt.Errorf("%w: %q", ErrMissingColumn, c)
+		}
+	}
+
+	var res Result
+	seen := map[string]int{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if !errors.As(err, &perr) {
+				return res, err
+			}
+			res.Errors = append(res.Errors, &RowError{Line: perr.StartLine, Err: perr.Err})
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		if blank(rec) {
+			continue
+		}
+		res.Rows++
+		value := func(col string) string {
+			if i := cols[col]; i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		m, rerr := build(opt, tmpl, value)
+		if rerr != nil {
+			rerr.Line = line
+			res.Errors = append(res.Errors, rerr)
+			continue
+		}
+		if !opt.KeepDuplicates {
+			if first, ok := seen[m.To]; ok {
+				res.Errors = append(res.Errors, &RowError{Line: line, Column: opt.Mapping.To, Value: value(opt.Mapping.To), Err: fmt.Errorf("%w (line %d)", ErrDuplicate, first)})
+				continue
+			}
+			seen[m.To] = line
+		}
+		res.Messages = append(res.Messages, m)
+		res.Lines = append(res.Lines, line)
+	}
+	return res, nil
+}
+
+// build creates the message of one row.
+func build(opt Options, tmpl *messages.Template, value func(col string) string) (messages.Message, *RowError) {
+	m := opt.Base
+	if tmpl != nil {
+		data := map[string]string{}
+		for _, col := range tmpl.Variables() {
+			// empty cells count as missing
+			if v := value(col); v != "" {
+				data[col] = v
+			}
+		}
+		var err error
+		if m, err = tmpl.Render(data); err != nil {
+			var merr *messages.MissingVariableError
+			if errors.As(err, &merr) {
+				return m, &RowError{Column: merr.Name, Err: ErrMissingValue}
+			}
+			return m, &RowError{Err: err}
+		}
+	}
+	raw := value(opt.Mapping.To)
+	to, ok := NormalizeNumber(raw)
+	if !ok {
+		return m, &RowError{Column: opt.Mapping.To, Value: raw, Err: ErrInvalidNumber}
+	}
+	m.To, m.ToList = to, nil
+
+	if len(opt.Mapping.CustomFields) > 0 || len(m.CustomFields) > 0 {
+		fields := maps.Clone(m.CustomFields)
+		if fields == nil {
+			fields = map[string]string{}
+		}
+		for name, col := range opt.Mapping.CustomFields {
+			fields[name] = value(col)
+		}
+		m.CustomFields = fields
+	}
+	if len(opt.Mapping.Variables) > 0 {
+		ko := messages.KakaoOptions{}
+		if m.KakaoOptions != nil {
+			ko = *m.KakaoOptions
+		}
+		vars := maps.Clone(ko.Variables)
+		if vars == nil {
+			vars = map[string]string{}
+		}
+		for name, col := range opt.Mapping.Variables {
+			v := value(col)
+			if v == "" {
+				return m, &RowError{Column: col, Err: ErrMissingValue}
+			}
+			vars[name] = v
+		}
+		ko.Variables = vars
+		m.KakaoOptions = &ko
+	}
+	if opt.Validate != nil {
+		if err := opt.Validate(m); err != nil {
+			return m, &RowError{Err: err}
+		}
+	}
+	return m, nil
+}
+
+// NormalizeNumber strips separators from a phone number and reports whether
+// the result looks like a phone number. A leading zero dropped by a
+// spreadsheet, as in 1012345678, is restored.
+func NormalizeNumber(s string) (string, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-' || r == ' ' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+	n := b.String()
+	if strings.HasPrefix(n, "1") && (len(n) == 9 || len(n) == 10) {
+		n = "0" + n
+	}
+	if len(n) < 8 || len(n) > 15 {
+		return "", false
+	}
+	return n, true
+}
+
+func decode(data []byte, enc Encoding) ([]byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if enc == EncodingUTF8 || (enc == EncodingAuto && utf8.Valid(data)) {
+		return data, nil
+	}
+	out, err := korean.EUCKR.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("recipients: decoding CP949: %w", err)
+	}
+	return out, nil
+}
+
+func blank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func valuesOf(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
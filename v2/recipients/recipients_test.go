@@ -0,0 +1,160 @@
+package recipients
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/messages"
+	"golang.org/x/text/encoding/korean"
+)
+
+const sample = "이름,전화번호,회원번호\n" +
+	"홍길동,010-1234-5678,A1\n" +
+	"김철수,1098765432,A2\n" +
+	",,\n" +
+	"이영희,010-1234-5678,A3\n" +
+	"박민수,전화없음,A4\n" +
+	",01055556666,A5\n"
+
+var opts = Options{
+	Base: messages.Message{From: "029302266", CustomFields: map[string]string{"campaign": "spring"}},
+	Mapping: Mapping{
+		To:           "전화번호",
+		CustomFields: map[string]string{"memberId": "회원번호"},
+	},
+	Template: "#{이름}님 안녕하세요",
+}
+
+func checkSample(t *testing.T, res Result) {
+	t.Helper()
+	if res.Rows != 5 {
+		t.Fatalf("rows = %d", res.Rows)
+	}
+	if len(res.Messages) != 2 {
+		t.Fatalf("messages: %+v", res.Messages)
+	}
+	m := res.Messages[0]
+	if m.To != "01012345678" || m.From != "029302266" || m.Text != "홍길동님 안녕하세요" || m.Type != messages.TypeSMS || m.CustomFields["memberId"] != "A1" || m.CustomFields["campaign"] != "spring" {
+		t.Fatalf("first message: %+v", m)
+	}
+	if res.Messages[1].To != "01098765432" || res.Lines[1] != 3 {
+		t.Fatalf("leading zero not restored: %+v line %d", res.Messages[1], res.Lines[1])
+	}
+	want := []struct {
+		line int
+		err  error
+	}{{5, ErrDuplicate}, {6, ErrInvalidNumber}, {7, ErrMissingValue}}
+	if len(res.Errors) != len(want) {
+		t.Fatalf("errors: %v", res.Errors)
+	}
+	for i, w := range want {
+		if e := res.Errors[i]; e.Line != w.line || !errors.Is(e, w.err) {
+			t.Fatalf("error %d = %v, want line %d %v", i, e, w.line, w.err)
+		}
+	}
+	if opts.Base.CustomFields["memberId"] != "" {
+		t.Fatal("base custom fields modified")
+	}
+}
+
+func TestParseUTF8(t *testing.T) {
+	res, err := Parse(strings.NewReader("\ufeff"+sample), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSample(t, res)
+}
+
+func TestParseCP949(t *testing.T) {
+	enc, err := korean.EUCKR.NewEncoder().String(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Parse(strings.NewReader(enc), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSample(t, res)
+}
+
+func TestParseKakaoVariablesAndDuplicates(t *testing.T) {
+	o := Options{
+		Base:           messages.Message{Type: "ATA", Text: "#{이름}님 안녕하세요", KakaoOptions: &messages.KakaoOptions{PfID: "PF", TemplateID: "T"}},
+		Mapping:        Mapping{To: "phone", Variables: map[string]string{"#{이름}": "name"}},
+		KeepDuplicates: true,
+		Comma:          ';',
+	}
+	res, err := Parse(strings.NewReader("phone;name\n01011112222;갑\n01011112222;을\n"), o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Messages) != 2 || len(res.Errors) != 0 {
+		t.Fatalf("result: %+v", res)
+	}
+	ko := res.Messages[1].KakaoOptions
+	if ko.PfID != "PF" || ko.Variables["#{이름}"] != "을" || res.Messages[0].KakaoOptions.Variables["#{이름}"] != "갑" {
+		t.Fatalf("kakao options: %+v", ko)
+	}
+	if res.Messages[0].Text != "#{이름}님 안녕하세요" {
+		t.Fatalf("AlimTalk text rendered locally: %q", res.Messages[0].Text)
+	}
+	if o.Base.KakaoOptions.Variables != nil {
+		t.Fatal("base kakao options modified")
+	}
+}
+
+func TestParseValidate(t *testing.T) {
+	o := Options{Mapping: Mapping{To: "phone"}, Validate: func(m messages.Message) error {
+		if !strings.HasPrefix(m.To, "010") {
+			return errors.New("mobile only")
+		}
+		return nil
+	}}
+	res, err := Parse(strings.NewReader("phone\n01011112222\n0212345678\n"), o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Messages) != 1 || len(res.Errors) != 1 || res.Errors[0].Line != 3 {
+		t.Fatalf("result: %+v", res)
+	}
+}
+
+func TestParseMissingColumn(t *testing.T) {
+	_, err := Parse(strings.NewReader("phone\n01011112222\n"), Options{Mapping: Mapping{To: "phone"}, Template: "#{name}"})
+	if !errors.Is(err, ErrMissingColumn) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestParseMalformedRow(t *testing.T) {
+	res, err := Parse(strings.NewReader("phone,name\n010\"1,x\n01011112222,y\n"), Options{Mapping: Mapping{To: "phone"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Messages) != 1 || res.Lines[0] != 3 {
+		t.Fatalf("result: %+v", res)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Line != 2 || !errors.Is(res.Errors[0], csv.ErrBareQuote) {
+		t.Fatalf("errors: %v", res.Errors)
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	for in, want := range map[string]string{
+		"010-1234-5678": "01012345678",
+		"02 123 4567":   "021234567",
+		"(031)123-4567": "0311234567",
+		"1012345678":    "01012345678",
+		"1588-1234":     "15881234",
+		"+82-10-1234":   "",
+		"1234":          "",
+		"010-1234-567a": "",
+	} {
+		got, ok := NormalizeNumber(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("NormalizeNumber(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+}
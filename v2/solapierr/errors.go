@@ -0,0 +1,173 @@
+// Package solapierr defines the errors returned by SOLAPI API calls.
+//
+// Callers can match categories with errors.Is against the exported sentinels,
+// or extract details with errors.As into *ApiError or *DefaultError:
+//
+//	if errors.Is(err, solapierr.ErrInsufficientBalance) { ... }
+//
+//	var apiErr *solapierr.ApiError
+//	if errors.As(err, &apiErr) && apiErr.Retryable() { ... }
+package solapierr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors for common API failure categories.
+var (
+	ErrInvalidCredentials  = errors.New("solapi: invalid credentials")
+	ErrInsufficientBalance = errors.New("solapi: insufficient balance")
+	ErrInvalidSender       = errors.New("solapi: invalid sender number")
+	ErrDuplicatedRecipient = errors.New("solapi: duplicated recipient")
+	ErrDuplicatedMessage   = errors.New("solapi: duplicated message")
+	ErrTemplateMismatch    = errors.New("solapi: template mismatch")
+	ErrRateLimited         = errors.New("solapi: rate limited")
+	ErrNotFound            = errors.New("solapi: not found")
+)
+
+// ErrCircuitOpen is returned without contacting the API while the client's
+// circuit breaker is open after repeated server failures.
+var ErrCircuitOpen = errors.New("solapi: circuit breaker is open")
+
+// codeSentinels maps API error codes to sentinels.
+var codeSentinels = map[string]error{
+	"InvalidApiKey":              ErrInvalidCredentials,
+	"InvalidAPIKey":              ErrInvalidCredentials,
+	"InvalidApiSecret":           ErrInvalidCredentials,
+	"SignatureDoesNotMatch":      ErrInvalidCredentials,
+	"InvalidSignature":           ErrInvalidCredentials,
+	"InvalidAuthorizationHeader": ErrInvalidCredentials,
+	"Unauthorized":               ErrInvalidCredentials,
+
+	"NotEnoughBalance":    ErrInsufficientBalance,
+	"NotEnoughPoint":      ErrInsufficientBalance,
+	"InsufficientBalance": ErrInsufficientBalance,
+
+	"InvalidSenderNumber":      ErrInvalidSender,
+	"UnregisteredSenderNumber": ErrInvalidSender,
+	"SenderNumberNotApproved":  ErrInvalidSender,
+	"BlockedSenderNumber":      ErrInvalidSender,
+
+	// a recipient repeated within one request, and a message already sent
+	// to the recipient, call for different fixes
+	"DuplicatedRecipient": ErrDuplicatedRecipient,
+	"DuplicatedMessage":   ErrDuplicatedMessage,
+
+	"TemplateMismatch":   ErrTemplateMismatch,
+	"TemplateNotMatched": ErrTemplateMismatch,
+	"NotMatchedTemplate": ErrTemplateMismatch,
+	"InvalidTemplate":    ErrTemplateMismatch,
+
+	"TooManyRequests":   ErrRateLimited,
+	"RateLimitExceeded": ErrRateLimited,
+
+	"NotFound":         ErrNotFound,
+	"ResourceNotFound": ErrNotFound,
+	"GroupNotFound":    ErrNotFound,
+	"MessageNotFound":  ErrNotFound,
+	"FileNotFound":     ErrNotFound,
+}
+
+// statusSentinels is consulted when the error code is unknown.
+var statusSentinels = map[int]error{
+	http.StatusUnauthorized:    ErrInvalidCredentials,
+	http.StatusNotFound:        ErrNotFound,
+	http.StatusTooManyRequests: ErrRateLimited,
+}
+
+// sentinelFor returns the sentinel matching code or status, or nil.
+func sentinelFor(code string, status int) error {
+	if s, ok := codeSentinels[code]; ok {
+		return s
+	}
+	return statusSentinels[status]
+}
+
+// ApiError is returned for 4xx responses carrying an API error body.
+type ApiError struct {
+	ErrorCode    string
+	ErrorMessage string
+	HTTPStatus   int
+	URL          string
+}
+
+func (e *ApiError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("api error %s (%d) %s: %s", e.ErrorCode, e.HTTPStatus, e.URL, e.ErrorMessage)
+}
+
+// Is reports whether the error belongs to the category of target.
+func (e *ApiError) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	s := sentinelFor(e.ErrorCode, e.HTTPStatus)
+	return s != nil && s == target
+}
+
+// Temporary reports whether the failure is expected to clear on its own.
+func (e *ApiError) Temporary() bool {
+	return e != nil && (e.HTTPStatus == http.StatusTooManyRequests || errors.Is(e, ErrRateLimited))
+}
+
+// Retryable reports whether repeating the same request may succeed.
+func (e *ApiError) Retryable() bool {
+	return e.Temporary()
+}
+
+// DefaultError is returned for non-4xx failures such as 5xx responses.
+type DefaultError struct {
+	ErrorCode    string
+	ErrorMessage string
+	Context      map[string]any
+}
+
+func (e *DefaultError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	if e.ErrorCode == "" {
+		return e.ErrorMessage
+	}
+	return fmt.Sprintf("%s: %s", e.ErrorCode, e.ErrorMessage)
+}
+
+// HTTPStatus returns the response status recorded in Context, or 0.
+func (e *DefaultError) HTTPStatus() int {
+	if e == nil {
+		return 0
+	}
+	status, _ := e.Context["status"].(int)
+	return status
+}
+
+// Is reports whether the error belongs to the category of target.
+func (e *DefaultError) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	s := sentinelFor(e.ErrorCode, e.HTTPStatus())
+	return s != nil && s == target
+}
+
+// Temporary reports whether the failure is expected to clear on its own.
+// Server errors and throttling are temporary.
+func (e *DefaultError) Temporary() bool {
+	status := e.HTTPStatus()
+	return status >= 500 || status == http.StatusTooManyRequests
+}
+
+// Retryable reports whether repeating the same request may succeed.
+// 500 responses are excluded because they usually indicate a request the
+// server cannot process rather than a transient outage.
+func (e *DefaultError) Retryable() bool {
+	switch e.HTTPStatus() {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
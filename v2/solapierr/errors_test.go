@@ -0,0 +1,50 @@
+package solapierr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestApiError_IsMapsCodesToSentinels(t *testing.T) {
+	cases := []struct {
+		err  *ApiError
+		want error
+	}{
+		{&ApiError{ErrorCode: "NotEnoughBalance", HTTPStatus: 400}, ErrInsufficientBalance},
+		{&ApiError{ErrorCode: "SignatureDoesNotMatch", HTTPStatus: 403}, ErrInvalidCredentials},
+		{&ApiError{ErrorCode: "Whatever", HTTPStatus: 404}, ErrNotFound},
+		{&ApiError{ErrorCode: "Whatever", HTTPStatus: 429}, ErrRateLimited},
+		{&ApiError{ErrorCode: "DuplicatedRecipient", HTTPStatus: 400}, ErrDuplicatedRecipient},
+		{&ApiError{ErrorCode: "DuplicatedMessage", HTTPStatus: 400}, ErrDuplicatedMessage},
+	}
+	for _, c := range cases {
+		wrapped := fmt.Errorf("send: %w", c.err)
+		if !errors.Is(wrapped, c.want) {
+			t.Fatalf("%s: expected errors.Is(%v)", c.err.ErrorCode, c.want)
+		}
+	}
+	if errors.Is(&ApiError{ErrorCode: "DuplicatedMessage"}, ErrDuplicatedRecipient) {
+		t.Fatalf("duplicated messages must not match duplicated recipients")
+	}
+	if errors.Is(&ApiError{ErrorCode: "NotEnoughBalance"}, ErrNotFound) {
+		t.Fatalf("unexpected sentinel match")
+	}
+}
+
+func TestRetryableAndTemporary(t *testing.T) {
+	if !(&ApiError{HTTPStatus: 429}).Retryable() {
+		t.Fatalf("429 should be retryable")
+	}
+	if (&ApiError{ErrorCode: "ValidationError", HTTPStatus: 400}).Retryable() {
+		t.Fatalf("validation errors should not be retryable")
+	}
+	e500 := &DefaultError{Context: map[string]any{"status": 500}}
+	if !e500.Temporary() || e500.Retryable() {
+		t.Fatalf("500: temporary=%v retryable=%v", e500.Temporary(), e500.Retryable())
+	}
+	e503 := &DefaultError{Context: map[string]any{"status": 503}}
+	if !e503.Retryable() {
+		t.Fatalf("503 should be retryable")
+	}
+}
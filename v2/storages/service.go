@@ -12,18 +12,18 @@ import (
 // Service provides storage-related APIs such as file upload.
 type Service struct {
 	baseURL    string
-	creds      auth.AuthenticationParameter
+	creds      auth.CredentialsProvider
 	httpClient *http.Client
 }
 
-func NewService(baseURL string, creds auth.AuthenticationParameter) *Service {
+func NewService(baseURL string, creds auth.CredentialsProvider) *Service {
 	return &Service{baseURL: baseURL, creds: creds, httpClient: http.DefaultClient}
 }
 
 // Upload calls POST /storage/v1/files with JSON body.
 func (s *Service) Upload(ctx context.Context, req UploadFileRequest) (UploadFileResponse, error) {
 	url := fmt.Sprintf("%s/storage/v1/files", s.baseURL)
-	httpReq := transport.DefaultRequest{URL: url, Method: "POST"}
-	ctx = transport.WithHTTPClient(ctx, s.httpClient)
+	httpReq := transport.DefaultRequest{URL: url, Method: "POST", Operation: "storages.upload"}
+	ctx = s.withTransport(ctx)
 	return transport.FetchJSON[UploadFileRequest, UploadFileResponse](ctx, s.creds, httpReq, &req)
 }
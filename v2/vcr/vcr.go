@@ -0,0 +1,311 @@
+// Package vcr records SOLAPI request/response pairs to a JSON cassette and
+// replays them, so tests can run deterministically without network access.
+//
+//	rec, err := vcr.New("testdata/send.json", vcr.ModeAuto)
+//	c := client.NewClient(key, secret).WithHTTPClient(rec.HTTPClient())
+//
+// Requests are matched on method, path, query and normalized JSON body. The
+// Authorization header is neither stored nor compared, since its salt, date
+// and signature change on every request. Credentials and the phone numbers
+// under number fields such as to and from are redacted before anything is
+// written; IDs and timestamps are kept, so a replayed ID can be used in the
+// next request.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/solapi/solapi-go/v2/internal/redact"
+)
+
+// ErrNoInteraction is returned in replay mode when no unused recorded
+// interaction matches a request.
+var ErrNoInteraction = errors.New("vcr: no matching interaction")
+
+// Mode selects whether a Recorder talks to the network.
+type Mode int
+
+const (
+	// ModeReplay serves requests from the cassette only.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests and overwrites the cassette.
+	ModeRecord
+	// ModeAuto replays when the cassette exists and records otherwise.
+	ModeAuto
+)
+
+// DefaultIgnoredFields are body fields left out of matching because they
+// describe the environment rather than the request.
+var DefaultIgnoredFields = []string{"osPlatform", "sdkVersion"}
+
+// Cassette is the file format of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded, redacted form of a request.
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded, redacted form of a response.
+type Response struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithTransport sets the transport used in record mode; the default is
+// http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) { r.base = rt }
+}
+
+// WithIgnoredFields replaces DefaultIgnoredFields.
+func WithIgnoredFields(fields ...string) Option {
+	return func(r *Recorder) { r.ignored = fields }
+}
+
+// Recorder is an http.RoundTripper that records or replays a cassette.
+type Recorder struct {
+	path    string
+	mode    Mode
+	base    http.RoundTripper
+	ignored []string
+	redact  redact.Redactor
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder for the cassette at path. In replay mode the
+// cassette must exist. ModeAuto resolves to replay or record here.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:    path,
+		mode:    mode,
+		base:    http.DefaultTransport,
+		ignored: DefaultIgnoredFields,
+		// message text is kept so cassettes stay readable
+		redact: redact.Redactor{ShowText: true, KeysOnly: true},
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+	if r.mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, fmt.Errorf("vcr: parse %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the effective mode.
+func (r *Recorder) Mode() Mode { return r.mode }
+
+// HTTPClient returns a client using r as its transport.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns a copy of the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := r.recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := r.matchKey(recorded)
+	for i, it := range r.cassette.Interactions {
+		if r.used[i] || r.matchKey(it.Request) != key {
+			continue
+		}
+		r.used[i] = true
+		return it.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.Path)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := make(map[string]string, len(resp.Header))
+	for k := range resp.Header {
+		// the body length may change with redaction
+		if k != "Set-Cookie" && k != "Content-Length" {
+			header[k] = resp.Header.Get(k)
+		}
+	}
+	it := Interaction{
+		Request:  recorded,
+		Response: Response{Status: resp.StatusCode, Header: header, Body: string(r.redactBody(body))},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, it)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// recordRequest returns the redacted form of req, leaving req.Body readable.
+func (r *Recorder) recordRequest(req *http.Request) (Request, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return Request{}, err
+		}
+		body = b
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	q := req.URL.Query()
+	for k, vs := range q {
+		for i, v := range vs {
+			vs[i] = r.redact.Value(k, v).(string)
+		}
+		q[k] = vs
+	}
+	return Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  q.Encode(),
+		Body:   string(r.redactBody(body)),
+	}, nil
+}
+
+func (r *Recorder) redactBody(b []byte) []byte {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+	return r.redact.JSON(b)
+}
+
+// matchKey normalizes a recorded request for comparison.
+func (r *Recorder) matchKey(req Request) string {
+	q, _ := url.ParseQuery(req.Query)
+	return req.Method + " " + req.Path + "?" + q.Encode() + "\n" + r.normalizeBody(req.Body)
+}
+
+// normalizeBody re-encodes a JSON body with sorted keys and without ignored
+// fields. Non-JSON bodies are compared verbatim.
+func (r *Recorder) normalizeBody(body string) string {
+	var v any
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	b, _ := json.Marshal(r.strip(v))
+	return string(b)
+}
+
+func (r *Recorder) strip(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for _, f := range r.ignored {
+			delete(x, f)
+		}
+		for k, e := range x {
+			x[k] = r.strip(e)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = r.strip(e)
+		}
+	}
+	return v
+}
+
+// save writes the cassette atomically. Callers must hold r.mu.
+func (r *Recorder) save() error {
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(r.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+func (res Response) toHTTP(req *http.Request) *http.Response {
+	h := make(http.Header, len(res.Header))
+	for k, v := range res.Header {
+		h.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.Status, http.StatusText(res.Status)),
+		StatusCode:    res.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader([]byte(res.Body))),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,172 @@
+package vcr
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solapi/solapi-go/v2/client"
+	"github.com/solapi/solapi-go/v2/groups"
+	"github.com/solapi/solapi-go/v2/messages"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "01012345678") {
+			t.Errorf("server must receive the real number: %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"groupInfo":{"groupId":"G1","count":{"total":1}},"messageList":[{"messageId":"M1","to":"01012345678"}]}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "send.json")
+	send := func(rt http.RoundTripper) (messages.DetailGroupMessageResponse, error) {
+		c := client.NewClient("key", "secret").WithHTTPClient(&http.Client{Transport: rewrite{srv.URL, rt}})
+		return c.Messages.Send(context.Background(), messages.Message{To: "01012345678", From: "029302266", Text: "hello"})
+	}
+
+	rec, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("expected record mode without cassette")
+	}
+	if _, err := send(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"01012345678", "029302266", "secret", "Authorization"} {
+		if strings.Contains(string(raw), leak) {
+			t.Errorf("cassette leaks %q:\n%s", leak, raw)
+		}
+	}
+
+	rep, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Mode() != ModeReplay {
+		t.Fatalf("expected replay mode with cassette")
+	}
+	srv.Close()
+	res, err := send(rep)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if res.GroupInfo.GroupID != "G1" || calls != 1 {
+		t.Fatalf("unexpected replay: %+v, calls=%d", res.GroupInfo, calls)
+	}
+
+	// each interaction is served once
+	if _, err := send(rep); !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestRecordAndReplay_GroupFlow(t *testing.T) {
+	const groupID = "G4V20231019123456ABCDEFG"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /messages/v4/groups":
+			w.Write([]byte(`{"groupId":"` + groupID + `","dateCreated":"2023-10-19T12:34:56.789Z"}`))
+		case "PUT /messages/v4/groups/" + groupID + "/messages":
+			w.Write([]byte(`{"groupId":"` + groupID + `","count":{"total":1}}`))
+		case "POST /messages/v4/groups/" + groupID + "/send":
+			w.Write([]byte(`{"groupInfo":{"groupId":"` + groupID + `","count":{"total":1}}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	flow := func(rt http.RoundTripper) (string, error) {
+		c := client.NewClient("key", "secret").WithHTTPClient(&http.Client{Transport: rewrite{srv.URL, rt}})
+		ctx := context.Background()
+		g, err := c.Groups.Create(ctx, groups.CreateGroupOptions{})
+		if err != nil {
+			return "", err
+		}
+		msgs := []messages.Message{{To: "01012345678", From: "029302266", Text: "hello"}}
+		if _, err := c.Groups.AddMessages(ctx, g.GroupID, groups.AddGroupMessagesRequest{Messages: msgs}); err != nil {
+			return "", err
+		}
+		res, err := c.Groups.Send(ctx, g.GroupID)
+		return res.GroupInfo.GroupID, err
+	}
+
+	path := filepath.Join(t.TempDir(), "group.json")
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := flow(rec); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(path)
+	if strings.Contains(string(raw), "01012345678") || !strings.Contains(string(raw), "2023-10-19T12:34:56.789Z") {
+		t.Fatalf("unexpected cassette:\n%s", raw)
+	}
+
+	rep, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+	id, err := flow(rep)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if id != groupID {
+		t.Fatalf("replayed group ID %q", id)
+	}
+}
+
+func TestReplay_BodyMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	os.WriteFile(path, []byte(`{"interactions":[{"request":{"method":"POST","path":"/x","body":"{\"a\":1,\"agent\":{\"osPlatform\":\"old\"}}"},"response":{"status":200,"body":"{}"}}]}`), 0o644)
+	rec, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc := rec.HTTPClient()
+	if _, err := hc.Post("http://example.invalid/x", "application/json", strings.NewReader(`{"a":2,"agent":{"osPlatform":"new"}}`)); !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("expected mismatch, got %v", err)
+	}
+	resp, err := hc.Post("http://example.invalid/x", "application/json", strings.NewReader(`{"agent":{"osPlatform":"new"},"a":1}`))
+	if err != nil {
+		t.Fatalf("ignored fields and key order must not matter: %v", err)
+	}
+	resp.Body.Close()
+}
+
+// rewrite points requests at the test server.
+type rewrite struct {
+	target string
+	rt     http.RoundTripper
+}
+
+func (r rewrite) RoundTrip(req *http.Request) (*http.Response, error) {
+	u := *req.URL
+	u.Scheme, u.Host = "http", strings.TrimPrefix(r.target, "http://")
+	req = req.Clone(req.Context())
+	req.URL = &u
+	req.Host = u.Host
+	return r.rt.RoundTrip(req)
+}